@@ -14,10 +14,118 @@ import (
 func main() {
 	var (
 		flagAll         bool
+		flagProfile     string
 		flagRules       string
+		flagExcludes    string
+		flagProfiles    bool
 		flagCustomRules cli.StringSlice
+		flagSecretNames cli.StringSlice
+
+		flagFeatureTpl     string
+		flagEnhancementTpl string
+		flagBreakingTpl    string
+
+		flagGuideTitle string
+		flagGuideNotes string
+
+		flagCurrentVersion  string
+		flagProposedVersion string
+
+		flagHistoryFilter string
+
+		flagBisectRule       string
+		flagBisectCustomRule string
+		flagBisectFilter     string
+
+		flagMatrix     bool
+		flagFullMatrix bool
+		flagExplain    bool
+
+		flagQuery   string
+		flagModules cli.StringSlice
+
+		flagLintRules       string
+		flagLintExcludes    string
+		flagLintCustomRules cli.StringSlice
+		flagListLint        bool
+
+		flagOnlyCaused bool
+
+		flagProviderName string
+		flagImpactState  bool
+
+		flagDryRun bool
 	)
 
+	ruleFlags := []cli.Flag{
+		&cli.BoolFlag{
+			Name:        "all",
+			EnvVars:     []string{"TFPLUGINBCD_ALL"},
+			Usage:       "Enable all pre-defined rules",
+			Destination: &flagAll,
+		},
+		&cli.StringFlag{
+			Name:        "profile",
+			EnvVars:     []string{"TFPLUGINBCD_PROFILE"},
+			Usage:       "A pre-defined profile name, which selects and tunes a set of pre-defined rules",
+			Destination: &flagProfile,
+		},
+		&cli.StringFlag{
+			Name:        "rules",
+			EnvVars:     []string{"TFPLUGINBCD_RULES"},
+			Usage:       "One or more pre-defined rule names (separated by comma)",
+			Destination: &flagRules,
+		},
+		&cli.StringFlag{
+			Name:        "exclude-rules",
+			EnvVars:     []string{"TFPLUGINBCD_EXCLUDE_RULES"},
+			Usage:       "One or more pre-defined rule names (separated by comma) to exclude from the selected rules",
+			Destination: &flagExcludes,
+		},
+		&cli.StringSliceFlag{
+			Name:        "custom-rule",
+			EnvVars:     []string{"TFPLUGINBCD_CUSTOM_RULE"},
+			Usage:       "Custom breaking change rule expression",
+			Destination: &flagCustomRules,
+		},
+		&cli.StringSliceFlag{
+			Name:        "secret-name-pattern",
+			EnvVars:     []string{"TFPLUGINBCD_SECRET_NAME_PATTERN"},
+			Usage:       "Regexp pattern of attribute names that look like secrets, used by the security rules (defaults to the built-in patterns)",
+			Destination: &flagSecretNames,
+		},
+	}
+
+	buildOpt := func() tfpluginbcd.Opt {
+		var opt tfpluginbcd.Opt
+		if flagAll {
+			var allRules []string
+			for name := range tfpluginbcd.Rules {
+				allRules = append(allRules, name)
+			}
+			slices.Sort(allRules)
+			opt.Rules = allRules
+		} else {
+			if flagRules != "" {
+				var rules []string
+				for _, rule := range strings.Split(flagRules, ",") {
+					rules = append(rules, strings.TrimSpace(rule))
+				}
+				opt.Rules = rules
+			}
+		}
+		if flagExcludes != "" {
+			for _, rule := range strings.Split(flagExcludes, ",") {
+				opt.ExcludeRules = append(opt.ExcludeRules, strings.TrimSpace(rule))
+			}
+		}
+		opt.Profile = flagProfile
+		opt.CustomRuleExprs = flagCustomRules.Value()
+		opt.SecretNamePatterns = flagSecretNames.Value()
+		opt.Explain = flagExplain
+		return opt
+	}
+
 	app := &cli.App{
 		Name:    "tfpluginbcd",
 		Version: getVersion(),
@@ -26,7 +134,50 @@ func main() {
 			{
 				Name:  "list",
 				Usage: "list pre-defined rules",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:        "profiles",
+						Usage:       "List pre-defined profiles instead",
+						Destination: &flagProfiles,
+					},
+					&cli.BoolFlag{
+						Name:        "lint",
+						Usage:       "List pre-defined lint rules instead",
+						Destination: &flagListLint,
+					},
+				},
 				Action: func(ctx *cli.Context) error {
+					if flagListLint {
+						var names []string
+						for name := range tfpluginbcd.LintRules {
+							names = append(names, name)
+						}
+						sort.StringSlice(names).Sort()
+
+						for _, name := range names {
+							rule := tfpluginbcd.LintRules[name]
+							fmt.Printf("%s [%s]: %s\n", rule.ID, rule.Category, rule.Description)
+						}
+						return nil
+					}
+					if flagProfiles {
+						var names []string
+						for name := range tfpluginbcd.Profiles {
+							names = append(names, name)
+						}
+						sort.StringSlice(names).Sort()
+
+						for _, name := range names {
+							profile := tfpluginbcd.Profiles[name]
+							var ids []string
+							for _, rule := range profile.Rules {
+								ids = append(ids, rule.ID)
+							}
+							fmt.Printf("%s: %s (%s)\n", profile.Name, profile.Description, strings.Join(ids, ", "))
+						}
+						return nil
+					}
+
 					var names []string
 					for name := range tfpluginbcd.Rules {
 						names = append(names, name)
@@ -35,7 +186,7 @@ func main() {
 
 					for _, name := range names {
 						rule := tfpluginbcd.Rules[name]
-						fmt.Printf("%s: %s\n", rule.ID, rule.Description)
+						fmt.Printf("%s [%s]: %s\n", rule.ID, rule.Category, rule.Description)
 					}
 					return nil
 				},
@@ -43,24 +194,320 @@ func main() {
 			{
 				Name:  "run",
 				Usage: "Run the breaking change detector and show breaking changes (all changes will be shown if no option is specified).",
-				Flags: []cli.Flag{
+				Flags: append(append([]cli.Flag{}, ruleFlags...),
 					&cli.BoolFlag{
-						Name:        "all",
-						EnvVars:     []string{"TFPLUGINBCD_ALL"},
-						Usage:       "Enable all pre-defined rules",
-						Destination: &flagAll,
+						Name:        "matrix",
+						Usage:       "Compare each of the leading (old) schema files (or the schema files under a directory, named by versions) to the last (new) one",
+						Destination: &flagMatrix,
+					},
+					&cli.BoolFlag{
+						Name:        "full-matrix",
+						Usage:       "Compare each pair of the ordered schema files (or the schema files under a directory, named by versions)",
+						Destination: &flagFullMatrix,
+					},
+					&cli.BoolFlag{
+						Name:        "explain",
+						Usage:       "Explain why each rule matched, by showing the rule expression, the evaluated change and the satisfied expressions",
+						Destination: &flagExplain,
+					},
+				),
+				Action: func(ctx *cli.Context) error {
+					if flagMatrix || flagFullMatrix {
+						if ctx.Args().Len() == 0 {
+							return fmt.Errorf("expected at least one arg")
+						}
+						out, err := tfpluginbcd.RunMatrix(ctx.Context, ctx.Args().Slice(), tfpluginbcd.MatrixOpt{Opt: buildOpt(), Full: flagFullMatrix})
+						if err != nil {
+							return err
+						}
+						fmt.Println(out)
+						return nil
+					}
+
+					if ctx.Args().Len() != 2 {
+						return fmt.Errorf("expected two args")
+					}
+
+					out, err := tfpluginbcd.Run(ctx.Context, ctx.Args().Get(0), ctx.Args().Get(1), buildOpt())
+					if err != nil {
+						return err
+					}
+					fmt.Println(out)
+					return nil
+				},
+			},
+			{
+				Name:  "changelog",
+				Usage: "Generate the changelog entries from the schema changes (breaking changes are detected by the rules of the \"ga\" profile if no rule option is specified).",
+				Flags: append(append([]cli.Flag{}, ruleFlags...),
+					&cli.StringFlag{
+						Name:        "feature-template",
+						Usage:       "The Go template of each line in the FEATURES section",
+						Value:       tfpluginbcd.DefaultChangelogFeatureTemplate,
+						Destination: &flagFeatureTpl,
+					},
+					&cli.StringFlag{
+						Name:        "enhancement-template",
+						Usage:       "The Go template of each line in the ENHANCEMENTS section",
+						Value:       tfpluginbcd.DefaultChangelogEnhancementTemplate,
+						Destination: &flagEnhancementTpl,
+					},
+					&cli.StringFlag{
+						Name:        "breaking-template",
+						Usage:       "The Go template of each line in the BREAKING CHANGES, SECURITY and NOTES sections",
+						Value:       tfpluginbcd.DefaultChangelogBreakingTemplate,
+						Destination: &flagBreakingTpl,
+					},
+				),
+				Action: func(ctx *cli.Context) error {
+					if ctx.Args().Len() != 2 {
+						return fmt.Errorf("expected two args")
+					}
+
+					opt := tfpluginbcd.ChangelogOpt{
+						Opt:                 buildOpt(),
+						FeatureTemplate:     flagFeatureTpl,
+						EnhancementTemplate: flagEnhancementTpl,
+						BreakingTemplate:    flagBreakingTpl,
+					}
+					out, err := tfpluginbcd.Changelog(ctx.Context, ctx.Args().Get(0), ctx.Args().Get(1), opt)
+					if err != nil {
+						return err
+					}
+					fmt.Println(out)
+					return nil
+				},
+			},
+			{
+				Name:  "upgrade-guide",
+				Usage: "Generate the Markdown upgrade guide from the breaking changes (breaking changes are detected by the rules of the \"ga\" profile if no rule option is specified).",
+				Flags: append(append([]cli.Flag{}, ruleFlags...),
+					&cli.StringFlag{
+						Name:        "title",
+						Usage:       "The title of the upgrade guide",
+						Value:       "Upgrade Guide",
+						Destination: &flagGuideTitle,
+					},
+					&cli.StringFlag{
+						Name:        "notes",
+						Usage:       "The path to a JSON file of hand-written migration hints, keyed by the object (e.g. \"resource/<type>\") and then the attribute/block path",
+						Destination: &flagGuideNotes,
+					},
+				),
+				Action: func(ctx *cli.Context) error {
+					if ctx.Args().Len() != 2 {
+						return fmt.Errorf("expected two args")
+					}
+
+					opt := tfpluginbcd.UpgradeGuideOpt{
+						Opt:       buildOpt(),
+						Title:     flagGuideTitle,
+						NotesFile: flagGuideNotes,
+					}
+					out, err := tfpluginbcd.UpgradeGuide(ctx.Context, ctx.Args().Get(0), ctx.Args().Get(1), opt)
+					if err != nil {
+						return err
+					}
+					fmt.Println(out)
+					return nil
+				},
+			},
+			{
+				Name:  "semver",
+				Usage: "Recommend the semantic version bump, i.e. major for any breaking change, minor for any addition, or patch otherwise (breaking changes are detected by the rules of the \"ga\" profile if no rule option is specified).",
+				Flags: append(append([]cli.Flag{}, ruleFlags...),
+					&cli.StringFlag{
+						Name:        "current-version",
+						Usage:       "The version of the old schema, used together with --proposed-version",
+						Destination: &flagCurrentVersion,
+					},
+					&cli.StringFlag{
+						Name:        "proposed-version",
+						Usage:       "The proposed version of the new schema, which fails the command if the bump from --current-version is too small",
+						Destination: &flagProposedVersion,
+					},
+				),
+				Action: func(ctx *cli.Context) error {
+					if ctx.Args().Len() != 2 {
+						return fmt.Errorf("expected two args")
+					}
+					if (flagCurrentVersion == "") != (flagProposedVersion == "") {
+						return fmt.Errorf("--current-version and --proposed-version must be specified together")
+					}
+
+					opt := tfpluginbcd.SemverOpt{
+						Opt:             buildOpt(),
+						CurrentVersion:  flagCurrentVersion,
+						ProposedVersion: flagProposedVersion,
+					}
+					out, err := tfpluginbcd.Semver(ctx.Context, ctx.Args().Get(0), ctx.Args().Get(1), opt)
+					if err != nil {
+						return err
+					}
+					fmt.Println(out)
+					return nil
+				},
+			},
+			{
+				Name:      "history",
+				Usage:     "Show the timeline of changes of each object across the ordered schema files (or a directory of schema files named by versions).",
+				ArgsUsage: "<schema file>... | <schema directory>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "filter",
+						Usage:       `Glob pattern of the object addresses, in form of "provider[.<path>]", "<type>[.<path>]" or "data.<type>[.<path>]" ("*" also matches ".")`,
+						Destination: &flagHistoryFilter,
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					if ctx.Args().Len() == 0 {
+						return fmt.Errorf("expected at least one arg")
+					}
+
+					out, err := tfpluginbcd.History(ctx.Args().Slice(), tfpluginbcd.HistoryOpt{Filter: flagHistoryFilter})
+					if err != nil {
+						return err
+					}
+					fmt.Println(out)
+					return nil
+				},
+			},
+			{
+				Name:      "bisect",
+				Usage:     "Binary search the ordered schema files (or a directory of schema files named by versions) for the first version where the rule starts matching, compared to the first schema.",
+				ArgsUsage: "<schema file>... | <schema directory>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "rule",
+						Usage:       "The pre-defined rule name",
+						Destination: &flagBisectRule,
 					},
+					&cli.StringFlag{
+						Name:        "custom-rule",
+						Usage:       "Custom breaking change rule expression",
+						Destination: &flagBisectCustomRule,
+					},
+					&cli.StringFlag{
+						Name:        "filter",
+						Usage:       `Glob pattern of the object addresses, in form of "provider[.<path>]", "<type>[.<path>]" or "data.<type>[.<path>]"`,
+						Destination: &flagBisectFilter,
+					},
+					&cli.StringSliceFlag{
+						Name:        "secret-name-pattern",
+						EnvVars:     []string{"TFPLUGINBCD_SECRET_NAME_PATTERN"},
+						Usage:       "Regexp pattern of attribute names that look like secrets, used by the security rules (defaults to the built-in patterns)",
+						Destination: &flagSecretNames,
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					if ctx.Args().Len() == 0 {
+						return fmt.Errorf("expected at least one arg")
+					}
+
+					opt := tfpluginbcd.BisectOpt{
+						Rule:               flagBisectRule,
+						CustomRuleExpr:     flagBisectCustomRule,
+						Filter:             flagBisectFilter,
+						SecretNamePatterns: flagSecretNames.Value(),
+					}
+					out, err := tfpluginbcd.Bisect(ctx.Context, ctx.Args().Slice(), opt)
+					if err != nil {
+						return err
+					}
+					fmt.Println(out)
+					return nil
+				},
+			},
+			{
+				Name:      "inspect",
+				Aliases:   []string{"query"},
+				Usage:     "Evaluate a Rego query against a single schema, which is normalized as the input (e.g. input.resources, input.attributes and input.blocks).",
+				ArgsUsage: "<schema file>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "query",
+						Aliases:     []string{"q"},
+						Usage:       "The Rego query",
+						Required:    true,
+						Destination: &flagQuery,
+					},
+					&cli.StringSliceFlag{
+						Name:        "module",
+						Usage:       "Path to a Rego module that can be referenced by the query",
+						Destination: &flagModules,
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					if ctx.Args().Len() != 1 {
+						return fmt.Errorf("expected one arg")
+					}
+
+					out, err := tfpluginbcd.Inspect(ctx.Context, ctx.Args().Get(0), tfpluginbcd.InspectOpt{Query: flagQuery, ModuleFiles: flagModules.Value()})
+					if err != nil {
+						return err
+					}
+					fmt.Println(out)
+					return nil
+				},
+			},
+			{
+				Name:      "lint",
+				Usage:     "Check the provider design conventions on a single schema (all pre-defined lint rules are used if no option is specified).",
+				ArgsUsage: "<schema file>",
+				Flags: []cli.Flag{
 					&cli.StringFlag{
 						Name:        "rules",
-						EnvVars:     []string{"TFPLUGINBCD_RULES"},
-						Usage:       "One or more pre-defined rule names (separated by comma)",
-						Destination: &flagRules,
+						EnvVars:     []string{"TFPLUGINBCD_LINT_RULES"},
+						Usage:       "One or more pre-defined lint rule names (separated by comma)",
+						Destination: &flagLintRules,
+					},
+					&cli.StringFlag{
+						Name:        "exclude-rules",
+						EnvVars:     []string{"TFPLUGINBCD_LINT_EXCLUDE_RULES"},
+						Usage:       "One or more pre-defined lint rule names (separated by comma) to exclude from the selected lint rules",
+						Destination: &flagLintExcludes,
 					},
 					&cli.StringSliceFlag{
 						Name:        "custom-rule",
-						EnvVars:     []string{"TFPLUGINBCD_CUSTOM_RULE"},
-						Usage:       "Custom breaking change rule expression",
-						Destination: &flagCustomRules,
+						EnvVars:     []string{"TFPLUGINBCD_LINT_CUSTOM_RULE"},
+						Usage:       "Custom lint rule expression",
+						Destination: &flagLintCustomRules,
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					if ctx.Args().Len() != 1 {
+						return fmt.Errorf("expected one arg")
+					}
+
+					var opt tfpluginbcd.LintOpt
+					if flagLintRules != "" {
+						for _, rule := range strings.Split(flagLintRules, ",") {
+							opt.Rules = append(opt.Rules, strings.TrimSpace(rule))
+						}
+					}
+					if flagLintExcludes != "" {
+						for _, rule := range strings.Split(flagLintExcludes, ",") {
+							opt.ExcludeRules = append(opt.ExcludeRules, strings.TrimSpace(rule))
+						}
+					}
+					opt.CustomRuleExprs = flagLintCustomRules.Value()
+
+					out, err := tfpluginbcd.Lint(ctx.Context, ctx.Args().Get(0), opt)
+					if err != nil {
+						return err
+					}
+					fmt.Println(out)
+					return nil
+				},
+			},
+			{
+				Name:  "references",
+				Usage: "Check the paths referenced by conflicts_with, required_with, exactly_one_of and at_least_one_of against the new schema, and show the broken ones (together with the change that broke it).",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:        "only-caused",
+						Usage:       "Only show the references that broke because of a change between the two schemas",
+						Destination: &flagOnlyCaused,
 					},
 				},
 				Action: func(ctx *cli.Context) error {
@@ -68,26 +515,99 @@ func main() {
 						return fmt.Errorf("expected two args")
 					}
 
-					var opt tfpluginbcd.Opt
-					if flagAll {
-						var allRules []string
-						for name := range tfpluginbcd.Rules {
-							allRules = append(allRules, name)
+					out, err := tfpluginbcd.References(ctx.Args().Get(0), ctx.Args().Get(1), tfpluginbcd.ReferencesOpt{OnlyCaused: flagOnlyCaused})
+					if err != nil {
+						return err
+					}
+					fmt.Println(out)
+					return nil
+				},
+			},
+			{
+				Name:      "impact",
+				Usage:     "Cross-reference the breaking changes against the Terraform configuration files (*.tf) under a directory, and show the affected usages (defaults to the \"ga\" profile).",
+				ArgsUsage: "<old schema> <new schema> <config dir> | <old schema> <new schema> <state file>...",
+				Flags: append(append([]cli.Flag{}, ruleFlags...),
+					&cli.StringFlag{
+						Name:        "provider-name",
+						Usage:       "The local name of the provider, used to match the provider blocks (defaults to the prefix of the resource types)",
+						Destination: &flagProviderName,
+					},
+					&cli.BoolFlag{
+						Name:        "state",
+						Usage:       "Cross-reference against the Terraform state files (version 4) instead, and show the affected resource instances",
+						Destination: &flagImpactState,
+					},
+				),
+				Action: func(ctx *cli.Context) error {
+					if flagImpactState {
+						if ctx.Args().Len() < 3 {
+							return fmt.Errorf("expected at least three args")
 						}
-						slices.Sort(allRules)
-						opt.Rules = allRules
-					} else {
-						if flagRules != "" {
-							var rules []string
-							for _, rule := range strings.Split(flagRules, ",") {
-								rules = append(rules, strings.TrimSpace(rule))
-							}
-							opt.Rules = rules
+						out, err := tfpluginbcd.StateImpact(ctx.Context, ctx.Args().Get(0), ctx.Args().Get(1), ctx.Args().Slice()[2:], tfpluginbcd.ImpactOpt{Opt: buildOpt()})
+						if err != nil {
+							return err
 						}
+						fmt.Println(out)
+						return nil
+					}
+
+					if ctx.Args().Len() != 3 {
+						return fmt.Errorf("expected three args")
+					}
+
+					out, err := tfpluginbcd.Impact(ctx.Context, ctx.Args().Get(0), ctx.Args().Get(1), ctx.Args().Get(2), tfpluginbcd.ImpactOpt{
+						Opt:          buildOpt(),
+						ProviderName: flagProviderName,
+					})
+					if err != nil {
+						return err
+					}
+					fmt.Println(out)
+					return nil
+				},
+			},
+			{
+				Name:  "migration-hints",
+				Usage: "Detect the renamed attributes and blocks, and emit the migration artifacts (the state upgrader skeleton and the configuration rewrite hints).",
+				Action: func(ctx *cli.Context) error {
+					if ctx.Args().Len() != 2 {
+						return fmt.Errorf("expected two args")
+					}
+
+					out, err := tfpluginbcd.MigrationHints(ctx.Args().Get(0), ctx.Args().Get(1))
+					if err != nil {
+						return err
+					}
+					fmt.Println(out)
+					return nil
+				},
+			},
+			{
+				Name:      "migrate",
+				Usage:     "Apply the mechanical fixes to the Terraform configuration files (*.tf) under a directory, for the renamed or removed arguments and the block nesting mode changes.",
+				ArgsUsage: "<old schema> <new schema> <config dir>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "provider-name",
+						Usage:       "The local name of the provider, used to match the provider blocks (defaults to the prefix of the resource types)",
+						Destination: &flagProviderName,
+					},
+					&cli.BoolFlag{
+						Name:        "dry-run",
+						Usage:       "Show the diffs instead of writing the files",
+						Destination: &flagDryRun,
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					if ctx.Args().Len() != 3 {
+						return fmt.Errorf("expected three args")
 					}
-					opt.CustomRuleExprs = flagCustomRules.Value()
 
-					out, err := tfpluginbcd.Run(ctx.Context, ctx.Args().Get(0), ctx.Args().Get(1), opt)
+					out, err := tfpluginbcd.Migrate(ctx.Args().Get(0), ctx.Args().Get(1), ctx.Args().Get(2), tfpluginbcd.MigrateOpt{
+						ProviderName: flagProviderName,
+						DryRun:       flagDryRun,
+					})
 					if err != nil {
 						return err
 					}
@@ -0,0 +1,132 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+type BisectOpt struct {
+	// Exactly one of Rule and CustomRuleExpr is specified.
+	// Rule is the ID of a pre-defined rule.
+	Rule string
+	// CustomRuleExpr is a custom rule expression.
+	CustomRuleExpr string
+
+	// Filter is a glob pattern of the addresses of the changed objects, see HistoryOpt.
+	Filter string
+
+	SecretNamePatterns []string
+}
+
+// Bisect binary searches the ordered schema files for the first version, where the rule starts matching (the filtered objects),
+// compared to the first schema file.
+// The paths can also be a single directory, where the schema files (*.json) are ordered by their versions (i.e. the file name without extension).
+func Bisect(ctx context.Context, paths []string, opt BisectOpt) (string, error) {
+	files, err := listSchemaFiles(paths)
+	if err != nil {
+		return "", err
+	}
+	cache := map[int]*ProviderSchema{}
+	load := func(i int) (*ProviderSchema, error) {
+		if sch, ok := cache[i]; ok {
+			return sch, nil
+		}
+		sch, err := loadSchema(files[i].Path)
+		if err != nil {
+			return nil, err
+		}
+		cache[i] = sch
+		return sch, nil
+	}
+
+	idx, results, err := bisect(ctx, len(files), load, opt)
+	if err != nil {
+		return "", err
+	}
+	if idx == -1 {
+		return fmt.Sprintf("No version matches, compared to %s", files[0].Version), nil
+	}
+
+	out := []string{fmt.Sprintf("%s is the first version that matches, compared to %s (the previous version is %s):", files[idx].Version, files[0].Version, files[idx-1].Version)}
+	for _, res := range results {
+		out = append(out, fmt.Sprintf("[%s] %s", res.Rule, res.Change.String()))
+	}
+	return strings.Join(out, "\n"), nil
+}
+
+// bisect returns the index of the first schema that matches, together with the matched results. It returns -1 if none matches.
+func bisect(ctx context.Context, n int, load func(int) (*ProviderSchema, error), opt BisectOpt) (int, []FilterResult, error) {
+	var rule Rule
+	switch {
+	case opt.Rule != "" && opt.CustomRuleExpr != "":
+		return 0, nil, fmt.Errorf("only one of the rule and the custom rule expression can be specified")
+	case opt.Rule != "":
+		var ok bool
+		rule, ok = Rules[opt.Rule]
+		if !ok {
+			return 0, nil, fmt.Errorf("undefined rule: %s", opt.Rule)
+		}
+	case opt.CustomRuleExpr != "":
+		rule = Rule{
+			ID:   "CUSTOM",
+			Expr: opt.CustomRuleExpr,
+		}
+	default:
+		return 0, nil, fmt.Errorf("either the rule or the custom rule expression is required")
+	}
+	if opt.Filter != "" {
+		if _, err := path.Match(opt.Filter, ""); err != nil {
+			return 0, nil, fmt.Errorf("invalid filter %q: %v", opt.Filter, err)
+		}
+	}
+
+	base, err := load(0)
+	if err != nil {
+		return 0, nil, err
+	}
+	match := func(i int) ([]FilterResult, error) {
+		sch, err := load(i)
+		if err != nil {
+			return nil, err
+		}
+		results, err := Filter(ctx, Compare(base, sch), []Rule{rule}, FilterOpt{SecretNamePatterns: opt.SecretNamePatterns})
+		if err != nil {
+			return nil, fmt.Errorf("filtering: %v", err)
+		}
+		if opt.Filter == "" {
+			return results, nil
+		}
+		var out []FilterResult
+		for _, res := range results {
+			if ok, _ := path.Match(opt.Filter, changeAddress(res.Change)); ok {
+				out = append(out, res)
+			}
+		}
+		return out, nil
+	}
+
+	// Invariant: files[lo] doesn't match, files[hi] matches
+	lo, hi := 0, n-1
+	hiResults, err := match(hi)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(hiResults) == 0 {
+		return -1, nil, nil
+	}
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		results, err := match(mid)
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(results) == 0 {
+			lo = mid
+		} else {
+			hi, hiResults = mid, results
+		}
+	}
+	return hi, hiResults, nil
+}
@@ -0,0 +1,92 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestBisect(t *testing.T) {
+	resourceSchema := func(attrs ...string) *ProviderSchema {
+		m := map[string]*AttributeSchema{}
+		for _, attr := range attrs {
+			m[attr] = &AttributeSchema{Type: cty.String, Optional: true}
+		}
+		return &ProviderSchema{
+			ResourceSchemas: map[string]*ResourceSchema{
+				"foo_resource": {
+					Block: &BlockSchema{
+						Attributes:   m,
+						NestedBlocks: map[string]*NestedBlockSchema{},
+					},
+				},
+			},
+		}
+	}
+	schemas := []*ProviderSchema{
+		resourceSchema("a", "b"),
+		resourceSchema("a", "b", "c"),
+		resourceSchema("b", "c"),
+		resourceSchema("b", "c"),
+		resourceSchema("c"),
+		resourceSchema("c"),
+		resourceSchema("c"),
+		resourceSchema("c"),
+	}
+
+	cases := []struct {
+		name        string
+		opt         BisectOpt
+		expectIdx   int
+		expectPaths [][]string
+		hasError    bool
+	}{
+		{
+			name:        "rule",
+			opt:         BisectOpt{Rule: "R003"},
+			expectIdx:   2,
+			expectPaths: [][]string{{"a"}},
+		},
+		{
+			name:        "rule with filter",
+			opt:         BisectOpt{Rule: "R003", Filter: "foo_resource.b"},
+			expectIdx:   4,
+			expectPaths: [][]string{{"b"}},
+		},
+		{
+			name:      "custom rule not matched",
+			opt:       BisectOpt{CustomRuleExpr: `c.kind == "resource"`},
+			expectIdx: -1,
+		},
+		{
+			name:     "no rule",
+			opt:      BisectOpt{},
+			hasError: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded := map[int]bool{}
+			load := func(i int) (*ProviderSchema, error) {
+				loaded[i] = true
+				return schemas[i], nil
+			}
+			idx, results, err := bisect(context.TODO(), len(schemas), load, tt.opt)
+			if tt.hasError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectIdx, idx)
+			var paths [][]string
+			for _, res := range results {
+				paths = append(paths, res.Change.(AttributeChange).Path)
+			}
+			require.Equal(t, tt.expectPaths, paths)
+			require.Less(t, len(loaded), len(schemas))
+		})
+	}
+}
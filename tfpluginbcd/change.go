@@ -18,10 +18,14 @@ type Change interface {
 type ChangeKind string
 
 const (
-	ChangeKindProvider  ChangeKind = "provider"
-	ChangeKindResource  ChangeKind = "resource"
-	ChangeKindAttribute ChangeKind = "attribute"
-	ChangeKindBlock     ChangeKind = "block"
+	ChangeKindProvider   ChangeKind = "provider"
+	ChangeKindResource   ChangeKind = "resource"
+	ChangeKindAttribute  ChangeKind = "attribute"
+	ChangeKindBlock      ChangeKind = "block"
+	ChangeKindConversion ChangeKind = "conversion"
+
+	ChangeKindFunction          ChangeKind = "function"
+	ChangeKindFunctionParameter ChangeKind = "function_parameter"
 )
 
 type ProviderChange struct {
@@ -54,6 +58,7 @@ type ResourceChange struct {
 	// Resource type
 	Type         string `json:"type"`
 	IsDataSource bool   `json:"is_data_source"`
+	IsEphemeral  bool   `json:"is_ephemeral"`
 
 	// Exactly one of them is true
 	IsAdd    bool `json:"is_add"`
@@ -63,6 +68,9 @@ type ResourceChange struct {
 	// Current represents the current schema of this resource, it is nil if IsDelete is true.
 	Current *Resource `json:"current,omitempty"`
 
+	// Previous represents the previous schema of this resource, it is non-nil only when IsDelete is true.
+	Previous *Resource `json:"previous,omitempty"`
+
 	// Modification represents the modification of this resource, it is non-nil only when IsModify is true.
 	Modification *ResourceModify `json:"modification,omitempty"`
 }
@@ -72,9 +80,12 @@ func (ResourceChange) isChange() {}
 func (c ResourceChange) String() string {
 	var msg string
 
-	if c.IsDataSource {
+	switch {
+	case c.IsDataSource:
 		msg += "Data Source"
-	} else {
+	case c.IsEphemeral:
+		msg += "Ephemeral Resource"
+	default:
 		msg += "Resource"
 	}
 
@@ -105,8 +116,10 @@ type Scope interface {
 type ScopeKind string
 
 const (
-	ScopeKindProvider ScopeKind = "provider"
-	ScopeKindResource ScopeKind = "resource"
+	ScopeKindProvider     ScopeKind = "provider"
+	ScopeKindResource     ScopeKind = "resource"
+	ScopeKindProviderMeta ScopeKind = "provider_meta"
+	ScopeKindIdentity     ScopeKind = "identity"
 )
 
 type ProviderScope struct{}
@@ -120,9 +133,50 @@ func (s ProviderScope) MarshalJSON() ([]byte, error) {
 	})
 }
 
+func scopeString(scope Scope) string {
+	switch scope := scope.(type) {
+	case ProviderScope:
+		return "provider config"
+	case ResourceScope:
+		if scope.IsDataSource {
+			return "data source " + scope.Type
+		}
+		if scope.IsEphemeral {
+			return "ephemeral resource " + scope.Type
+		}
+		return "resource " + scope.Type
+	case ProviderMetaScope:
+		return "provider meta"
+	case IdentityScope:
+		return "identity of resource " + scope.Type
+	}
+	return ""
+}
+
+// scopeAddress returns the address of the scope, i.e. one of "provider", "provider_meta", "<type>", "data.<type>", "ephemeral.<type>"
+// and "identity.<type>", which also identifies the scope.
+func scopeAddress(scope Scope) string {
+	switch scope := scope.(type) {
+	case ResourceScope:
+		if scope.IsDataSource {
+			return "data." + scope.Type
+		}
+		if scope.IsEphemeral {
+			return "ephemeral." + scope.Type
+		}
+		return scope.Type
+	case ProviderMetaScope:
+		return "provider_meta"
+	case IdentityScope:
+		return "identity." + scope.Type
+	}
+	return "provider"
+}
+
 type ResourceScope struct {
 	Type         string `json:"type"`
 	IsDataSource bool   `json:"is_data_source"`
+	IsEphemeral  bool   `json:"is_ephemeral"`
 }
 
 func (ResourceScope) isScope() {}
@@ -134,6 +188,33 @@ func (s ResourceScope) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// ProviderMetaScope is the scope of the provider meta schema, which is configured in the "provider_meta" block of the
+// "terraform" block by modules.
+type ProviderMetaScope struct{}
+
+func (ProviderMetaScope) isScope() {}
+
+func (s ProviderMetaScope) MarshalJSON() ([]byte, error) {
+	type alias ProviderMetaScope
+	return injectMarshal(alias(s), func(m map[string]interface{}) {
+		m["kind"] = ScopeKindProviderMeta
+	})
+}
+
+// IdentityScope is the scope of the identity schema of a resource, which is configured in the "identity" of the import blocks.
+type IdentityScope struct {
+	Type string `json:"type"`
+}
+
+func (IdentityScope) isScope() {}
+
+func (s IdentityScope) MarshalJSON() ([]byte, error) {
+	type alias IdentityScope
+	return injectMarshal(alias(s), func(m map[string]interface{}) {
+		m["kind"] = ScopeKindIdentity
+	})
+}
+
 type AttributeChange struct {
 	Scope `json:"scope"`
 	Path  []string `json:"path"`
@@ -146,6 +227,9 @@ type AttributeChange struct {
 	// Current represents the current schema of this attribute, it is nil if IsDelete is true.
 	Current *Attribute `json:"current,omitempty"`
 
+	// Previous represents the previous schema of this attribute, it is non-nil only when IsDelete is true.
+	Previous *Attribute `json:"previous,omitempty"`
+
 	// Modification represents the modification of this attribute, it is non-nil only when IsModify is true.
 	Modification *AttributeModify `json:"modification,omitempty"`
 }
@@ -157,16 +241,7 @@ func (c AttributeChange) String() string {
 
 	msg += fmt.Sprintf("Attribute %q of", strings.Join(c.Path, "."))
 
-	switch scope := c.Scope.(type) {
-	case ProviderScope:
-		msg += " provider config"
-	case ResourceScope:
-		if scope.IsDataSource {
-			msg += " data source " + scope.Type
-		} else {
-			msg += " resource " + scope.Type
-		}
-	}
+	msg += " " + scopeString(c.Scope)
 
 	msg += " is"
 
@@ -201,6 +276,9 @@ type BlockChange struct {
 	// Current represents the current schema of this block, it is nil if IsDelete is true.
 	Current *Block `json:"current,omitempty"`
 
+	// Previous represents the previous schema of this block, it is non-nil only when IsDelete is true.
+	Previous *Block `json:"previous,omitempty"`
+
 	// Modification represents the modification of this block, it is non-nil only when IsModify is true.
 	Modification *BlockModify `json:"modification,omitempty"`
 }
@@ -212,16 +290,7 @@ func (c BlockChange) String() string {
 
 	msg += fmt.Sprintf("Block %q of", strings.Join(c.Path, "."))
 
-	switch scope := c.Scope.(type) {
-	case ProviderScope:
-		msg += " provider config"
-	case ResourceScope:
-		if scope.IsDataSource {
-			msg += " data source " + scope.Type
-		} else {
-			msg += " resource " + scope.Type
-		}
-	}
+	msg += " " + scopeString(c.Scope)
 
 	msg += " is"
 
@@ -244,6 +313,57 @@ func (c BlockChange) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// ConversionChange is a nested block converted to an attribute of the same path, or vice versa, which changes the configuration syntax
+// (e.g. `foo {}` vs `foo = {}`).
+type ConversionChange struct {
+	Scope `json:"scope"`
+	Path  []string `json:"path"`
+
+	// Exactly one of them is true
+	IsBlockToAttribute bool `json:"is_block_to_attribute"`
+	IsAttributeToBlock bool `json:"is_attribute_to_block"`
+
+	// PreviousBlock and CurrentAttribute are non-nil only when IsBlockToAttribute is true.
+	PreviousBlock    *Block     `json:"previous_block,omitempty"`
+	CurrentAttribute *Attribute `json:"current_attribute,omitempty"`
+
+	// PreviousAttribute and CurrentBlock are non-nil only when IsAttributeToBlock is true.
+	PreviousAttribute *Attribute `json:"previous_attribute,omitempty"`
+	CurrentBlock      *Block     `json:"current_block,omitempty"`
+}
+
+func (ConversionChange) isChange() {}
+
+func (c ConversionChange) String() string {
+	var msg string
+
+	if c.IsBlockToAttribute {
+		msg += fmt.Sprintf("Block %q of", strings.Join(c.Path, "."))
+	} else {
+		msg += fmt.Sprintf("Attribute %q of", strings.Join(c.Path, "."))
+	}
+
+	msg += " " + scopeString(c.Scope)
+
+	msg += " is " + c.verb()
+
+	return msg
+}
+
+func (c ConversionChange) verb() string {
+	if c.IsBlockToAttribute {
+		return "converted to an attribute"
+	}
+	return "converted to a block"
+}
+
+func (c ConversionChange) MarshalJSON() ([]byte, error) {
+	type alias ConversionChange
+	return injectMarshal(alias(c), func(m map[string]interface{}) {
+		m["kind"] = ChangeKindConversion
+	})
+}
+
 type Modification[T any] struct {
 	From T `json:"from"`
 	To   T `json:"to"`
@@ -251,53 +371,126 @@ type Modification[T any] struct {
 
 type Resource struct {
 	SchemaVersion int `json:"schema_version"`
+	Docs
 }
 
 type ResourceModify struct {
 	SchemaVersion *Modification[int] `json:"schema_version,omitempty"`
+	DocsModify
 }
 
 func (m ResourceModify) String() string {
+	return strings.Join(m.items(), ", ")
+}
+
+// items returns the description of each modified field.
+func (m ResourceModify) items() []string {
 	var l []string
 	if m.SchemaVersion != nil {
 		l = append(l, fmt.Sprintf("schema version: %d -> %d", m.SchemaVersion.From, m.SchemaVersion.To))
 	}
-	return strings.Join(l, ", ")
+	l = append(l, m.DocsModify.items()...)
+	return l
+}
+
+// DocsModify is the modification of the docs of a resource, an attribute or a block.
+type DocsModify struct {
+	Description        *Modification[string] `json:"description,omitempty"`
+	Deprecated         *Modification[bool]   `json:"deprecated,omitempty"`
+	DeprecationMessage *Modification[string] `json:"deprecation_message,omitempty"`
+}
+
+func (m DocsModify) items() []string {
+	var l []string
+	if m.Description != nil {
+		l = append(l, "description changed")
+	}
+	if m.Deprecated != nil {
+		l = append(l, fmt.Sprintf("deprecated: %t -> %t", m.Deprecated.From, m.Deprecated.To))
+	}
+	if m.DeprecationMessage != nil {
+		l = append(l, fmt.Sprintf("deprecation message: %q -> %q", m.DeprecationMessage.From, m.DeprecationMessage.To))
+	}
+	return l
+}
+
+// NewDocsModify returns the modification of the docs, or nil if nothing is changed.
+func NewDocsModify(odocs, ndocs Docs) *DocsModify {
+	isChanged := false
+	ret := &DocsModify{}
+
+	if odocs.Description != ndocs.Description {
+		isChanged = true
+		ret.Description = &Modification[string]{
+			From: odocs.Description,
+			To:   ndocs.Description,
+		}
+	}
+	if odocs.Deprecated != ndocs.Deprecated {
+		isChanged = true
+		ret.Deprecated = &Modification[bool]{
+			From: odocs.Deprecated,
+			To:   ndocs.Deprecated,
+		}
+	}
+	if odocs.DeprecationMessage != ndocs.DeprecationMessage {
+		isChanged = true
+		ret.DeprecationMessage = &Modification[string]{
+			From: odocs.DeprecationMessage,
+			To:   ndocs.DeprecationMessage,
+		}
+	}
+
+	if !isChanged {
+		return nil
+	}
+	return ret
 }
 
 type Attribute struct {
-	Type          cty.Type    `json:"type"`
-	Required      bool        `json:"required"`
-	Optional      bool        `json:"optional"`
-	Computed      bool        `json:"computed"`
-	ForceNew      bool        `json:"force_new"`
-	Default       interface{} `json:"default"`
-	Sensitive     bool        `json:"sensitive"`
-	ConflictsWith []string    `json:"conflicts_with"`
-	ExactlyOneOf  []string    `json:"exactly_one_of"`
-	AtLeastOneOf  []string    `json:"at_least_one_of"`
-	RequiredWith  []string    `json:"required_with"`
+	Type          cty.Type           `json:"type"`
+	NestingMode   schema.NestingMode `json:"nesting_mode,omitempty"`
+	Required      bool               `json:"required"`
+	Optional      bool               `json:"optional"`
+	Computed      bool               `json:"computed"`
+	ForceNew      bool               `json:"force_new"`
+	Default       interface{}        `json:"default"`
+	Sensitive     bool               `json:"sensitive"`
+	ConflictsWith []string           `json:"conflicts_with"`
+	ExactlyOneOf  []string           `json:"exactly_one_of"`
+	AtLeastOneOf  []string           `json:"at_least_one_of"`
+	RequiredWith  []string           `json:"required_with"`
+	Docs
 }
 
 type AttributeModify struct {
-	Type          *Modification[cty.Type] `json:"type,omitempty"`
-	Required      *Modification[bool]     `json:"required,omitempty"`
-	Optional      *Modification[bool]     `json:"optional,omitempty"`
-	Computed      *Modification[bool]     `json:"computed,omitempty"`
-	ForceNew      *Modification[bool]     `json:"force_new,omitempty"`
-	Default       *Modification[any]      `json:"default,omitempty"`
-	Sensitive     *Modification[bool]     `json:"sensitive,omitempty"`
-	ConflictsWith *Modification[[]string] `json:"conflicts_with,omitempty"`
-	RequiredWith  *Modification[[]string] `json:"required_with,omitempty"`
-	ExactlyOneOf  *Modification[[]string] `json:"exactly_one_of,omitempty"`
-	AtLeastOneOf  *Modification[[]string] `json:"at_least_one_of,omitempty"`
+	Type          *Modification[cty.Type]           `json:"type,omitempty"`
+	NestingMode   *Modification[schema.NestingMode] `json:"nesting_mode,omitempty"`
+	Required      *Modification[bool]               `json:"required,omitempty"`
+	Optional      *Modification[bool]               `json:"optional,omitempty"`
+	Computed      *Modification[bool]               `json:"computed,omitempty"`
+	ForceNew      *Modification[bool]               `json:"force_new,omitempty"`
+	Default       *Modification[DefaultValue]       `json:"default,omitempty"`
+	Sensitive     *Modification[bool]               `json:"sensitive,omitempty"`
+	ConflictsWith *Modification[[]string]           `json:"conflicts_with,omitempty"`
+	RequiredWith  *Modification[[]string]           `json:"required_with,omitempty"`
+	ExactlyOneOf  *Modification[[]string]           `json:"exactly_one_of,omitempty"`
+	AtLeastOneOf  *Modification[[]string]           `json:"at_least_one_of,omitempty"`
+	DocsModify
 }
 
 func (m AttributeModify) String() string {
+	return strings.Join(m.items(), ", ")
+}
+
+func (m AttributeModify) items() []string {
 	var l []string
 	if m.Type != nil {
 		l = append(l, fmt.Sprintf("type: %s -> %s", m.Type.From.FriendlyName(), m.Type.To.FriendlyName()))
 	}
+	if m.NestingMode != nil {
+		l = append(l, fmt.Sprintf("nesting mode: %v -> %v", m.NestingMode.From, m.NestingMode.To))
+	}
 	if m.Required != nil {
 		l = append(l, fmt.Sprintf("required: %t -> %t", m.Required.From, m.Required.To))
 	}
@@ -311,7 +504,7 @@ func (m AttributeModify) String() string {
 		l = append(l, fmt.Sprintf("force new: %t -> %t", m.ForceNew.From, m.ForceNew.To))
 	}
 	if m.Default != nil {
-		l = append(l, fmt.Sprintf("default: %v -> %v", m.Default.From, m.Default.To))
+		l = append(l, fmt.Sprintf("default: %s -> %s", m.Default.From, m.Default.To))
 	}
 	if m.Sensitive != nil {
 		l = append(l, fmt.Sprintf("sensitive: %t -> %t", m.Sensitive.From, m.Sensitive.To))
@@ -328,7 +521,8 @@ func (m AttributeModify) String() string {
 	if m.AtLeastOneOf != nil {
 		l = append(l, fmt.Sprintf("at least one of: [%s] -> [%s]", strings.Join(m.AtLeastOneOf.From, ", "), strings.Join(m.AtLeastOneOf.To, ", ")))
 	}
-	return strings.Join(l, ", ")
+	l = append(l, m.DocsModify.items()...)
+	return l
 }
 
 type Block struct {
@@ -343,6 +537,7 @@ type Block struct {
 	RequiredWith  []string           `json:"required_with"`
 	MinItems      int                `json:"min_items"`
 	MaxItems      int                `json:"max_items"`
+	Docs
 }
 
 type BlockModify struct {
@@ -357,9 +552,14 @@ type BlockModify struct {
 	RequiredWith  *Modification[[]string]           `json:"required_with,omitempty"`
 	MinItems      *Modification[int]                `json:"min_items,omitempty"`
 	MaxItems      *Modification[int]                `json:"max_items,omitempty"`
+	DocsModify
 }
 
 func (m BlockModify) String() string {
+	return strings.Join(m.items(), ", ")
+}
+
+func (m BlockModify) items() []string {
 	var l []string
 	if m.NestingMode != nil {
 		l = append(l, fmt.Sprintf("nesting mode: %v -> %v", m.NestingMode.From, m.NestingMode.To))
@@ -394,15 +594,21 @@ func (m BlockModify) String() string {
 	if m.MaxItems != nil {
 		l = append(l, fmt.Sprintf("max items: %d -> %d", m.MaxItems.From, m.MaxItems.To))
 	}
-	return strings.Join(l, ", ")
+	l = append(l, m.DocsModify.items()...)
+	return l
 }
 
-func NewAttribute(attr *schema.Attribute) *Attribute {
+func NewAttribute(attr *AttributeSchema) *Attribute {
 	if attr == nil {
 		return nil
 	}
+	var nestingMode schema.NestingMode
+	if nt := attr.NestedType; nt != nil {
+		nestingMode = nt.NestingMode
+	}
 	return &Attribute{
-		Type:          attr.Type,
+		Type:          attr.ImpliedType(),
+		NestingMode:   nestingMode,
 		Required:      attr.Required,
 		Optional:      attr.Optional,
 		Computed:      attr.Computed,
@@ -413,10 +619,11 @@ func NewAttribute(attr *schema.Attribute) *Attribute {
 		ExactlyOneOf:  attr.ExactlyOneOf,
 		AtLeastOneOf:  attr.AtLeastOneOf,
 		RequiredWith:  attr.RequiredWith,
+		Docs:          attr.Docs,
 	}
 }
 
-func NewNestedBlock(blk *schema.NestedBlock) *Block {
+func NewNestedBlock(blk *NestedBlockSchema) *Block {
 	if blk == nil {
 		return nil
 	}
@@ -432,18 +639,29 @@ func NewNestedBlock(blk *schema.NestedBlock) *Block {
 		RequiredWith:  blk.RequiredWith,
 		MinItems:      blk.MinItems,
 		MaxItems:      blk.MaxItems,
+		Docs:          blockDocs(blk.Block),
+	}
+}
+
+func NewResource(res *ResourceSchema) *Resource {
+	if res == nil {
+		return nil
+	}
+	return &Resource{
+		SchemaVersion: res.SchemaVersion,
+		Docs:          blockDocs(res.Block),
 	}
 }
 
-func NewAttributeModify(oattr schema.Attribute, nattr schema.Attribute) *AttributeModify {
+func NewAttributeModify(oattr AttributeSchema, nattr AttributeSchema) *AttributeModify {
 	isChanged := false
 	ret := &AttributeModify{}
 
-	if !oattr.Type.Equals(nattr.Type) {
+	if otype, ntype := oattr.ImpliedType(), nattr.ImpliedType(); !otype.Equals(ntype) {
 		isChanged = true
 		ret.Type = &Modification[cty.Type]{
-			From: oattr.Type,
-			To:   nattr.Type,
+			From: otype,
+			To:   ntype,
 		}
 	}
 	if oattr.Required != nattr.Required {
@@ -474,11 +692,11 @@ func NewAttributeModify(oattr schema.Attribute, nattr schema.Attribute) *Attribu
 			To:   nattr.ForceNew,
 		}
 	}
-	if oattr.Default != nattr.Default {
+	if !valueEqual(oattr.Default, oattr.Type, nattr.Default, nattr.Type) {
 		isChanged = true
-		ret.Default = &Modification[any]{
-			From: oattr.Default,
-			To:   nattr.Default,
+		ret.Default = &Modification[DefaultValue]{
+			From: newDefaultValue(oattr.Default, oattr.Type),
+			To:   newDefaultValue(nattr.Default, nattr.Type),
 		}
 	}
 	if oattr.Sensitive != nattr.Sensitive {
@@ -522,7 +740,7 @@ func NewAttributeModify(oattr schema.Attribute, nattr schema.Attribute) *Attribu
 	return ret
 }
 
-func NewBlockModify(oblk schema.NestedBlock, nblk schema.NestedBlock) *BlockModify {
+func NewBlockModify(oblk NestedBlockSchema, nblk NestedBlockSchema) *BlockModify {
 	isChanged := false
 	ret := &BlockModify{}
 
@@ -132,9 +132,9 @@ func TestChangeString(t *testing.T) {
 					// 	From: false,
 					// 	To:   false,
 					// },
-					Default: &Modification[any]{
-						From: nil,
-						To:   10,
+					Default: &Modification[DefaultValue]{
+						From: DefaultValue{cty.NullVal(cty.String)},
+						To:   DefaultValue{cty.StringVal("10")},
 					},
 					Sensitive: &Modification[bool]{
 						From: false,
@@ -163,7 +163,7 @@ func TestChangeString(t *testing.T) {
 				"required: false -> true, " +
 				"optional: true -> false, " +
 				"computed: false -> true, " +
-				"default: <nil> -> 10, " +
+				`default: null -> "10", ` +
 				"sensitive: false -> true, " +
 				`conflicts with: [] -> [a], ` +
 				`required with: [a] -> [b], ` +
@@ -280,3 +280,37 @@ func TestChangeString(t *testing.T) {
 		})
 	}
 }
+
+func TestAttributeModifyDefaultString(t *testing.T) {
+	cases := []struct {
+		name   string
+		oattr  AttributeSchema
+		nattr  AttributeSchema
+		expect string
+	}{
+		{
+			name:   "set default",
+			oattr:  AttributeSchema{Type: cty.Set(cty.String), Default: []interface{}{"b", "a"}},
+			nattr:  AttributeSchema{Type: cty.Set(cty.String), Default: []interface{}{"c", "a"}},
+			expect: `default: ["a", "b"] -> ["a", "c"]`,
+		},
+		{
+			name:   "type changed",
+			oattr:  AttributeSchema{Type: cty.Number, Default: 1},
+			nattr:  AttributeSchema{Type: cty.String, Default: "2"},
+			expect: `type: number -> string, default: 1 -> "2"`,
+		},
+		{
+			name:   "unconvertible defaults",
+			oattr:  AttributeSchema{Type: cty.String, Default: []interface{}{"a"}},
+			nattr:  AttributeSchema{Type: cty.String, Default: []interface{}{"b"}},
+			expect: `default: ["a"] -> ["b"]`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expect, NewAttributeModify(tt.oattr, tt.nattr).String())
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package tfpluginbcd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+const (
+	DefaultChangelogFeatureTemplate     = "* **New {{ if .IsDataSource }}Data Source{{ else if .IsEphemeral }}Ephemeral Resource{{ else if eq .Kind \"function\" }}Function{{ else }}Resource{{ end }}:** `{{ .Type }}`"
+	DefaultChangelogEnhancementTemplate = "* {{ .Object }}: new {{ .Kind }} {{ .Path }}"
+	DefaultChangelogBreakingTemplate    = "* {{ .Object }}: {{ .Message }} ({{ .Rule }})"
+)
+
+type ChangelogOpt struct {
+	// Opt selects the rules, whose matches are grouped into sections by the rule category. Defaults to the "ga" profile.
+	Opt
+
+	// The line templates (in Go template syntax) of each section, whose data is a ChangelogEntry.
+	// The BreakingTemplate is used by all the sections of the rule matches, i.e. the breaking changes, security and notes.
+	FeatureTemplate     string
+	EnhancementTemplate string
+	BreakingTemplate    string
+}
+
+// ChangelogEntry is the data used to render a changelog line.
+type ChangelogEntry struct {
+	// Object is the changed object in the changelog convention, i.e. "provider", "provider-meta", "resource/<type>",
+	// "data-source/<type>", "ephemeral-resource/<type>", "resource-identity/<type>" or "function/<name>".
+	Object       string
+	Type         string
+	IsDataSource bool
+	IsEphemeral  bool
+	Kind         ChangeKind
+	// Path is the dot separated path of the changed attribute or block, or the name of the changed function parameter.
+	// It is empty for provider, resource or function level changes.
+	Path string
+	// Rule, RuleCategory and RuleDescription are the ID, category and description of the matched rule, which are only set for
+	// the rule matches. The RuleCategory is empty for the custom rules.
+	Rule            string
+	RuleCategory    RuleCategory
+	RuleDescription string
+	// Message is the string representation of the change.
+	Message string
+	Change  Change
+}
+
+func Changelog(ctx context.Context, opath, npath string, opt ChangelogOpt) (string, error) {
+	osch, nsch, err := loadSchemas(opath, npath)
+	if err != nil {
+		return "", err
+	}
+	return changelog(ctx, *osch, *nsch, opt)
+}
+
+func changelog(ctx context.Context, osch, nsch ProviderSchema, opt ChangelogOpt) (string, error) {
+	if !opt.hasRules() {
+		opt.Profile = "ga"
+	}
+	featureTpl, err := parseChangelogTemplate("feature", opt.FeatureTemplate, DefaultChangelogFeatureTemplate)
+	if err != nil {
+		return "", err
+	}
+	enhancementTpl, err := parseChangelogTemplate("enhancement", opt.EnhancementTemplate, DefaultChangelogEnhancementTemplate)
+	if err != nil {
+		return "", err
+	}
+	breakingTpl, err := parseChangelogTemplate("breaking", opt.BreakingTemplate, DefaultChangelogBreakingTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	rules, err := buildRules(opt.Opt)
+	if err != nil {
+		return "", err
+	}
+	changes := Compare(&osch, &nsch)
+	results, err := Filter(ctx, changes, rules, opt.filterOpt())
+	if err != nil {
+		return "", fmt.Errorf("filtering: %v", err)
+	}
+
+	var features, enhancements, breakings, securities, notes []string
+	for _, change := range changes {
+		switch change := change.(type) {
+		case ResourceChange:
+			if !change.IsAdd {
+				continue
+			}
+			line, err := renderChangelogEntry(featureTpl, newChangelogEntry(change))
+			if err != nil {
+				return "", err
+			}
+			features = append(features, line)
+		case FunctionChange:
+			if !change.IsAdd {
+				continue
+			}
+			line, err := renderChangelogEntry(featureTpl, newChangelogEntry(change))
+			if err != nil {
+				return "", err
+			}
+			features = append(features, line)
+		case AttributeChange:
+			if !change.IsAdd || !change.Current.Optional {
+				continue
+			}
+			line, err := renderChangelogEntry(enhancementTpl, newChangelogEntry(change))
+			if err != nil {
+				return "", err
+			}
+			enhancements = append(enhancements, line)
+		case BlockChange:
+			if !change.IsAdd || change.Current.Required {
+				continue
+			}
+			line, err := renderChangelogEntry(enhancementTpl, newChangelogEntry(change))
+			if err != nil {
+				return "", err
+			}
+			enhancements = append(enhancements, line)
+		}
+	}
+	for _, res := range results {
+		entry := newChangelogEntry(res.Change)
+		entry.Rule = res.Rule
+		if rule, ok := Rules[res.Rule]; ok {
+			entry.RuleCategory = rule.Category
+			entry.RuleDescription = rule.Description
+		}
+		line, err := renderChangelogEntry(breakingTpl, entry)
+		if err != nil {
+			return "", err
+		}
+		// The matches of the custom rules are regarded as breaking changes.
+		switch entry.RuleCategory {
+		case RuleCategorySecurity:
+			securities = append(securities, line)
+		case RuleCategoryDeprecation:
+			notes = append(notes, line)
+		default:
+			breakings = append(breakings, line)
+		}
+	}
+
+	var sections []string
+	for _, sec := range []struct {
+		title string
+		lines []string
+	}{
+		{"FEATURES", features},
+		{"ENHANCEMENTS", enhancements},
+		{"BREAKING CHANGES", breakings},
+		{"SECURITY", securities},
+		{"NOTES", notes},
+	} {
+		if len(sec.lines) == 0 {
+			continue
+		}
+		sections = append(sections, sec.title+":\n\n"+strings.Join(sec.lines, "\n"))
+	}
+	return strings.Join(sections, "\n\n"), nil
+}
+
+func parseChangelogTemplate(name, tpl, defaultTpl string) (*template.Template, error) {
+	if tpl == "" {
+		tpl = defaultTpl
+	}
+	t, err := template.New(name).Parse(tpl)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the %s template: %v", name, err)
+	}
+	return t, nil
+}
+
+func renderChangelogEntry(tpl *template.Template, entry ChangelogEntry) (string, error) {
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, entry); err != nil {
+		return "", fmt.Errorf("rendering the %s template: %v", tpl.Name(), err)
+	}
+	return buf.String(), nil
+}
+
+func newChangelogEntry(change Change) ChangelogEntry {
+	entry := ChangelogEntry{
+		Message: change.String(),
+		Change:  change,
+	}
+	var scope Scope
+	switch change := change.(type) {
+	case ProviderChange:
+		entry.Kind = ChangeKindProvider
+		scope = ProviderScope{}
+	case ResourceChange:
+		entry.Kind = ChangeKindResource
+		scope = ResourceScope{Type: change.Type, IsDataSource: change.IsDataSource, IsEphemeral: change.IsEphemeral}
+	case AttributeChange:
+		entry.Kind = ChangeKindAttribute
+		entry.Path = strings.Join(change.Path, ".")
+		scope = change.Scope
+	case BlockChange:
+		entry.Kind = ChangeKindBlock
+		entry.Path = strings.Join(change.Path, ".")
+		scope = change.Scope
+	case ConversionChange:
+		entry.Kind = ChangeKindConversion
+		entry.Path = strings.Join(change.Path, ".")
+		scope = change.Scope
+	case FunctionChange:
+		entry.Kind = ChangeKindFunction
+		entry.Type = change.Name
+		entry.Object = "function/" + change.Name
+	case FunctionParameterChange:
+		entry.Kind = ChangeKindFunctionParameter
+		entry.Type = change.Function
+		entry.Object = "function/" + change.Function
+		if change.Current != nil {
+			entry.Path = change.Current.Name
+		} else {
+			entry.Path = change.Previous.Name
+		}
+	}
+	switch scope := scope.(type) {
+	case ProviderScope:
+		entry.Object = "provider"
+	case ResourceScope:
+		entry.Type = scope.Type
+		entry.IsDataSource = scope.IsDataSource
+		entry.IsEphemeral = scope.IsEphemeral
+		switch {
+		case scope.IsDataSource:
+			entry.Object = "data-source/" + scope.Type
+		case scope.IsEphemeral:
+			entry.Object = "ephemeral-resource/" + scope.Type
+		default:
+			entry.Object = "resource/" + scope.Type
+		}
+	case ProviderMetaScope:
+		entry.Object = "provider-meta"
+	case IdentityScope:
+		entry.Type = scope.Type
+		entry.Object = "resource-identity/" + scope.Type
+	}
+	return entry
+}
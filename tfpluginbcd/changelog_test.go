@@ -0,0 +1,175 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/magodo/tfpluginschema/schema"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestChangelog(t *testing.T) {
+	osch := ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"old_attr": {
+							Type:     cty.String,
+							Optional: true,
+						},
+						"password": {
+							Type:      cty.String,
+							Optional:  true,
+							Sensitive: true,
+						},
+						"legacy_attr": {
+							Type:     cty.String,
+							Optional: true,
+						},
+					},
+					NestedBlocks: map[string]*NestedBlockSchema{},
+				},
+			},
+		},
+	}
+	nsch := ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"new_attr": {
+							Type:     cty.String,
+							Optional: true,
+						},
+						"new_computed_attr": {
+							Type:     cty.String,
+							Computed: true,
+						},
+						"password": {
+							Type:     cty.String,
+							Optional: true,
+						},
+						"legacy_attr": {
+							Type:     cty.String,
+							Optional: true,
+							Docs:     Docs{Deprecated: true},
+						},
+					},
+					NestedBlocks: map[string]*NestedBlockSchema{
+						"new_blk": {
+							NestingMode: schema.NestingList,
+							Optional:    true,
+							Block: &BlockSchema{
+								Attributes:   map[string]*AttributeSchema{},
+								NestedBlocks: map[string]*NestedBlockSchema{},
+							},
+						},
+					},
+				},
+			},
+		},
+		DataSourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes:   map[string]*AttributeSchema{},
+					NestedBlocks: map[string]*NestedBlockSchema{},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		opt      ChangelogOpt
+		expect   string
+		hasError bool
+	}{
+		{
+			name: "default",
+			expect: `FEATURES:
+
+* **New Data Source:** ` + "`foo_resource`" + `
+
+ENHANCEMENTS:
+
+* resource/foo_resource: new attribute new_attr
+* resource/foo_resource: new block new_blk
+
+BREAKING CHANGES:
+
+* resource/foo_resource: Attribute "old_attr" of resource foo_resource is deleted (R003)`,
+		},
+		{
+			name: "custom templates and rules",
+			opt: ChangelogOpt{
+				Opt: Opt{
+					Rules: []string{"R013"},
+				},
+				FeatureTemplate:     "- {{ .Type }}",
+				EnhancementTemplate: "- {{ .Type }}.{{ .Path }}",
+				BreakingTemplate:    "- {{ .Type }}.{{ .Path }}: {{ .RuleDescription }}",
+			},
+			expect: `FEATURES:
+
+- foo_resource
+
+ENHANCEMENTS:
+
+- foo_resource.new_attr
+- foo_resource.new_blk
+
+BREAKING CHANGES:
+
+- foo_resource.old_attr: A required or optional argument is deleted`,
+		},
+		{
+			name: "rule categories",
+			opt: ChangelogOpt{
+				Opt: Opt{
+					Rules: []string{"R003", "S001", "D001"},
+				},
+			},
+			expect: `FEATURES:
+
+* **New Data Source:** ` + "`foo_resource`" + `
+
+ENHANCEMENTS:
+
+* resource/foo_resource: new attribute new_attr
+* resource/foo_resource: new block new_blk
+
+BREAKING CHANGES:
+
+* resource/foo_resource: Attribute "old_attr" of resource foo_resource is deleted (R003)
+
+SECURITY:
+
+* resource/foo_resource: Attribute "password" of resource foo_resource is changed: sensitive: true -> false (S001)
+
+NOTES:
+
+* resource/foo_resource: Attribute "legacy_attr" of resource foo_resource is changed: deprecated: false -> true (D001)`,
+		},
+		{
+			name: "invalid template",
+			opt: ChangelogOpt{
+				FeatureTemplate: "{{ .Type",
+			},
+			hasError: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := changelog(context.TODO(), osch, nsch, tt.opt)
+			if tt.hasError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, actual)
+		})
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"golang.org/x/exp/slices"
 )
 
-func Compare(oldSch, newSch *schema.ProviderSchema) []Change {
+func Compare(oldSch, newSch *ProviderSchema) []Change {
 	var changes []Change
 
 	switch {
@@ -19,13 +19,20 @@ func Compare(oldSch, newSch *schema.ProviderSchema) []Change {
 		changes = append(changes, ProviderChange{IsDelete: true})
 	}
 
-	changes = append(changes, compareResources(oldSch.DataSourceSchemas, newSch.DataSourceSchemas, true)...)
-	changes = append(changes, compareResources(oldSch.ResourceSchemas, newSch.ResourceSchemas, false)...)
+	// The absent provider meta schema or resource identity schema is regarded as an empty block, as only the usages of
+	// its attributes are affected.
+	changes = append(changes, compareBlock(ProviderMetaScope{}, []string{}, blockOrEmpty(oldSch.providerMetaBlock()), blockOrEmpty(newSch.providerMetaBlock()))...)
+
+	changes = append(changes, compareResources(oldSch.DataSourceSchemas, newSch.DataSourceSchemas, true, false)...)
+	changes = append(changes, compareResources(oldSch.ResourceSchemas, newSch.ResourceSchemas, false, false)...)
+	changes = append(changes, compareIdentities(oldSch.resourceIdentityBlocks(), newSch.resourceIdentityBlocks(), newSch.ResourceSchemas)...)
+	changes = append(changes, compareResources(oldSch.EphemeralResourceSchemas, newSch.EphemeralResourceSchemas, false, true)...)
+	changes = append(changes, compareFunctions(oldSch.Functions, newSch.Functions)...)
 
 	return changes
 }
 
-func compareResources(orm, nrm map[string]*schema.Resource, isDataSource bool) []Change {
+func compareResources(orm, nrm map[string]*ResourceSchema, isDataSource, isEphemeral bool) []Change {
 	var changes []Change
 	for _, rt := range mapSortedKeys(orm) {
 		ores := orm[rt]
@@ -35,31 +42,41 @@ func compareResources(orm, nrm map[string]*schema.Resource, isDataSource bool) [
 			changes = append(changes, ResourceChange{
 				Type:         rt,
 				IsDataSource: isDataSource,
+				IsEphemeral:  isEphemeral,
 				IsDelete:     true,
+				Previous:     NewResource(ores),
 			})
 			continue
 		}
 		// Update
+		isChanged := false
+		modification := &ResourceModify{}
 		if ores.SchemaVersion != nres.SchemaVersion {
+			isChanged = true
+			modification.SchemaVersion = &Modification[int]{
+				From: ores.SchemaVersion,
+				To:   nres.SchemaVersion,
+			}
+		}
+		if docsModification := NewDocsModify(blockDocs(ores.Block), blockDocs(nres.Block)); docsModification != nil {
+			isChanged = true
+			modification.DocsModify = *docsModification
+		}
+		if isChanged {
 			changes = append(changes, ResourceChange{
 				Type:         rt,
 				IsDataSource: isDataSource,
+				IsEphemeral:  isEphemeral,
 				IsModify:     true,
-				Current: &Resource{
-					SchemaVersion: nres.SchemaVersion,
-				},
-				Modification: &ResourceModify{
-					SchemaVersion: &Modification[int]{
-						From: ores.SchemaVersion,
-						To:   nres.SchemaVersion,
-					},
-				},
+				Current:      NewResource(nres),
+				Modification: modification,
 			})
 		}
 		// Inner
 		scope := ResourceScope{
 			Type:         rt,
 			IsDataSource: isDataSource,
+			IsEphemeral:  isEphemeral,
 		}
 		changes = append(changes, compareBlock(scope, []string{}, ores.Block, nres.Block)...)
 	}
@@ -71,10 +88,9 @@ func compareResources(orm, nrm map[string]*schema.Resource, isDataSource bool) [
 			changes = append(changes, ResourceChange{
 				Type:         rt,
 				IsDataSource: isDataSource,
+				IsEphemeral:  isEphemeral,
 				IsAdd:        true,
-				Current: &Resource{
-					SchemaVersion: nres.SchemaVersion,
-				},
+				Current:      NewResource(nres),
 			})
 			continue
 		}
@@ -83,43 +99,116 @@ func compareResources(orm, nrm map[string]*schema.Resource, isDataSource bool) [
 	return changes
 }
 
-func compareBlock(scope Scope, path []string, oblk, nblk *schema.Block) []Change {
+// compareIdentities compares the resource identity schemas. The newly added identity schemas are skipped, as there is no usage of them yet.
+// The identity schemas of the deleted resources (i.e. absent from the new resource schemas) are also skipped, as the resource deletions
+// are reported instead.
+func compareIdentities(obm, nbm map[string]*BlockSchema, nrm map[string]*ResourceSchema) []Change {
+	var changes []Change
+	for _, rt := range mapSortedKeys(obm) {
+		if _, ok := nrm[rt]; !ok {
+			continue
+		}
+		changes = append(changes, compareBlock(IdentityScope{Type: rt}, []string{}, blockOrEmpty(obm[rt]), blockOrEmpty(nbm[rt]))...)
+	}
+	return changes
+}
+
+func compareBlock(scope Scope, path []string, oblk, nblk *BlockSchema) []Change {
+	return compareBlockMembers(scope, path, oblk.Attributes, oblk.NestedBlocks, nblk.Attributes, nblk.NestedBlocks)
+}
+
+// compareBlockMembers compares the attributes and the nested blocks of a block (or of a nested attribute, which has no nested block).
+// The members that are converted between an attribute and a nested block are compared as conversions.
+func compareBlockMembers(scope Scope, path []string, oattrs map[string]*AttributeSchema, oblks map[string]*NestedBlockSchema, nattrs map[string]*AttributeSchema, nblks map[string]*NestedBlockSchema) []Change {
 	var changes []Change
 
-	for _, name := range mapSortedKeys(oblk.Attributes) {
-		oattr := oblk.Attributes[name]
-		changes = append(changes, compareAttribute(scope, append(path, name), oattr, nblk.Attributes[name])...)
+	oattrsLeft, nblksLeft, toBlocks := splitCommonKeys(oattrs, nblks)
+	oblksLeft, nattrsLeft, toAttrs := splitCommonKeys(oblks, nattrs)
+
+	changes = append(changes, compareAttributes(scope, path, oattrsLeft, nattrsLeft)...)
+
+	for _, name := range mapSortedKeys(oblksLeft) {
+		oNestBlk := oblksLeft[name]
+		changes = append(changes, compareNestedBlock(scope, append(append([]string{}, path...), name), oNestBlk, nblksLeft[name])...)
 	}
-	for _, name := range mapSortedKeys(nblk.Attributes) {
-		nattr := nblk.Attributes[name]
-		if _, ok := oblk.Attributes[name]; ok {
+	for _, name := range mapSortedKeys(nblksLeft) {
+		nNestBlk := nblksLeft[name]
+		if _, ok := oblksLeft[name]; ok {
 			continue
 		}
-		changes = append(changes, compareAttribute(scope, append(path, name), nil, nattr)...)
+		changes = append(changes, compareNestedBlock(scope, append(append([]string{}, path...), name), nil, nNestBlk)...)
+	}
+
+	for _, name := range toAttrs {
+		changes = append(changes, compareBlockToAttribute(scope, append(append([]string{}, path...), name), oblks[name], nattrs[name])...)
+	}
+	for _, name := range toBlocks {
+		changes = append(changes, compareAttributeToBlock(scope, append(append([]string{}, path...), name), oattrs[name], nblks[name])...)
+	}
+
+	return changes
+}
+
+// compareBlockToAttribute compares a nested block with the attribute it is converted to. If it is converted to a nested attribute,
+// the members of both are further compared.
+func compareBlockToAttribute(scope Scope, path []string, oblk *NestedBlockSchema, nattr *AttributeSchema) []Change {
+	changes := []Change{
+		ConversionChange{
+			Scope:              scope,
+			Path:               path,
+			IsBlockToAttribute: true,
+			PreviousBlock:      NewNestedBlock(oblk),
+			CurrentAttribute:   NewAttribute(nattr),
+		},
+	}
+	if nt := nattr.NestedType; nt != nil && oblk.Block != nil {
+		changes = append(changes, compareBlockMembers(scope, path, oblk.Block.Attributes, oblk.Block.NestedBlocks, nt.Attributes, nil)...)
+	}
+	return changes
+}
+
+// compareAttributeToBlock compares an attribute with the nested block it is converted to. If it is converted from a nested attribute,
+// the members of both are further compared.
+func compareAttributeToBlock(scope Scope, path []string, oattr *AttributeSchema, nblk *NestedBlockSchema) []Change {
+	changes := []Change{
+		ConversionChange{
+			Scope:              scope,
+			Path:               path,
+			IsAttributeToBlock: true,
+			PreviousAttribute:  NewAttribute(oattr),
+			CurrentBlock:       NewNestedBlock(nblk),
+		},
+	}
+	if ot := oattr.NestedType; ot != nil && nblk.Block != nil {
+		changes = append(changes, compareBlockMembers(scope, path, ot.Attributes, nil, nblk.Block.Attributes, nblk.Block.NestedBlocks)...)
 	}
+	return changes
+}
 
-	for _, name := range mapSortedKeys(oblk.NestedBlocks) {
-		oNestBlk := oblk.NestedBlocks[name]
-		changes = append(changes, compareNestedBlock(scope, append(path, name), oNestBlk, nblk.NestedBlocks[name])...)
+func compareAttributes(scope Scope, path []string, oattrs, nattrs map[string]*AttributeSchema) []Change {
+	var changes []Change
+	for _, name := range mapSortedKeys(oattrs) {
+		oattr := oattrs[name]
+		changes = append(changes, compareAttribute(scope, append(append([]string{}, path...), name), oattr, nattrs[name])...)
 	}
-	for _, name := range mapSortedKeys(nblk.NestedBlocks) {
-		nNestBlk := nblk.NestedBlocks[name]
-		if _, ok := oblk.NestedBlocks[name]; ok {
+	for _, name := range mapSortedKeys(nattrs) {
+		nattr := nattrs[name]
+		if _, ok := oattrs[name]; ok {
 			continue
 		}
-		changes = append(changes, compareNestedBlock(scope, append(path, name), nil, nNestBlk)...)
+		changes = append(changes, compareAttribute(scope, append(append([]string{}, path...), name), nil, nattr)...)
 	}
-
 	return changes
 }
 
-func compareAttribute(scope Scope, path []string, oattr, nattr *schema.Attribute) []Change {
+func compareAttribute(scope Scope, path []string, oattr, nattr *AttributeSchema) []Change {
 	if nattr == nil {
 		return []Change{
 			AttributeChange{
 				Scope:    scope,
 				Path:     path,
 				IsDelete: true,
+				Previous: NewAttribute(oattr),
 			},
 		}
 	}
@@ -136,26 +225,47 @@ func compareAttribute(scope Scope, path []string, oattr, nattr *schema.Attribute
 
 	modification := NewAttributeModify(*oattr, *nattr)
 	if modification == nil {
-		return nil
+		modification = &AttributeModify{}
+	}
+	ont, nnt := oattr.NestedType, nattr.NestedType
+	if ont != nil && nnt != nil {
+		// The type of a nested attribute is implied by its nesting mode and its nested attributes, which are compared instead.
+		modification.Type = nil
+		if ont.NestingMode != nnt.NestingMode {
+			modification.NestingMode = &Modification[schema.NestingMode]{
+				From: ont.NestingMode,
+				To:   nnt.NestingMode,
+			}
+		}
+	}
+	if docsModification := NewDocsModify(oattr.Docs, nattr.Docs); docsModification != nil {
+		modification.DocsModify = *docsModification
 	}
-	return []Change{
-		AttributeChange{
+
+	var changes []Change
+	if len(modification.items()) != 0 {
+		changes = append(changes, AttributeChange{
 			Scope:        scope,
 			Path:         path,
 			IsModify:     true,
 			Current:      NewAttribute(nattr),
 			Modification: modification,
-		},
+		})
+	}
+	if ont != nil && nnt != nil {
+		changes = append(changes, compareAttributes(scope, path, ont.Attributes, nnt.Attributes)...)
 	}
+	return changes
 }
 
-func compareNestedBlock(scope Scope, path []string, oblk, nblk *schema.NestedBlock) []Change {
+func compareNestedBlock(scope Scope, path []string, oblk, nblk *NestedBlockSchema) []Change {
 	if nblk == nil {
 		return []Change{
 			BlockChange{
 				Scope:    scope,
 				Path:     path,
 				IsDelete: true,
+				Previous: NewNestedBlock(oblk),
 			},
 		}
 	}
@@ -172,6 +282,12 @@ func compareNestedBlock(scope Scope, path []string, oblk, nblk *schema.NestedBlo
 
 	var changes []Change
 	modification := NewBlockModify(*oblk, *nblk)
+	if docsModification := NewDocsModify(blockDocs(oblk.Block), blockDocs(nblk.Block)); docsModification != nil {
+		if modification == nil {
+			modification = &BlockModify{}
+		}
+		modification.DocsModify = *docsModification
+	}
 	if modification != nil {
 		changes = append(changes, BlockChange{
 			Scope:        scope,
@@ -194,3 +310,32 @@ func mapSortedKeys[T any](m map[string]T) []string {
 	slices.Sort(keys)
 	return keys
 }
+
+// splitCommonKeys returns the copies of both maps without their common keys, together with the sorted common keys.
+func splitCommonKeys[T, U any](m1 map[string]T, m2 map[string]U) (map[string]T, map[string]U, []string) {
+	var common []string
+	out1 := map[string]T{}
+	for key, v := range m1 {
+		if _, ok := m2[key]; ok {
+			common = append(common, key)
+			continue
+		}
+		out1[key] = v
+	}
+	out2 := map[string]U{}
+	for key, v := range m2 {
+		if _, ok := m1[key]; ok {
+			continue
+		}
+		out2[key] = v
+	}
+	slices.Sort(common)
+	return out1, out2, common
+}
+
+func blockOrEmpty(blk *BlockSchema) *BlockSchema {
+	if blk == nil {
+		return &BlockSchema{}
+	}
+	return blk
+}
@@ -13,15 +13,15 @@ func TestCompareAttribute(t *testing.T) {
 		name   string
 		scope  Scope
 		path   []string
-		oattr  *schema.Attribute
-		nattr  *schema.Attribute
+		oattr  *AttributeSchema
+		nattr  *AttributeSchema
 		expect []Change
 	}{
 		{
 			name:  "Attribute deleted",
 			scope: ResourceScope{Type: "foo_resource"},
 			path:  []string{"attr1"},
-			oattr: &schema.Attribute{
+			oattr: &AttributeSchema{
 				Type:     cty.Bool,
 				Required: true,
 			},
@@ -31,6 +31,10 @@ func TestCompareAttribute(t *testing.T) {
 					Scope:    ResourceScope{Type: "foo_resource"},
 					Path:     []string{"attr1"},
 					IsDelete: true,
+					Previous: &Attribute{
+						Type:     cty.Bool,
+						Required: true,
+					},
 				},
 			},
 		},
@@ -39,7 +43,7 @@ func TestCompareAttribute(t *testing.T) {
 			scope: ResourceScope{Type: "foo_resource"},
 			path:  []string{"attr1"},
 			oattr: nil,
-			nattr: &schema.Attribute{
+			nattr: &AttributeSchema{
 				Type:     cty.Bool,
 				Required: true,
 			},
@@ -59,11 +63,11 @@ func TestCompareAttribute(t *testing.T) {
 			name:  "Attribute single updated",
 			scope: ResourceScope{Type: "foo_resource"},
 			path:  []string{"attr1"},
-			oattr: &schema.Attribute{
+			oattr: &AttributeSchema{
 				Type:     cty.Bool,
 				Required: true,
 			},
-			nattr: &schema.Attribute{
+			nattr: &AttributeSchema{
 				Type:     cty.String,
 				Required: true,
 			},
@@ -89,11 +93,11 @@ func TestCompareAttribute(t *testing.T) {
 			name:  "Attribute multiple updates",
 			scope: ResourceScope{Type: "foo_resource"},
 			path:  []string{"attr1"},
-			oattr: &schema.Attribute{
+			oattr: &AttributeSchema{
 				Type:     cty.Bool,
 				Required: true,
 			},
-			nattr: &schema.Attribute{
+			nattr: &AttributeSchema{
 				Type:     cty.String,
 				Optional: true,
 			},
@@ -123,6 +127,71 @@ func TestCompareAttribute(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "Attribute default in different numeric representation",
+			scope: ResourceScope{Type: "foo_resource"},
+			path:  []string{"attr1"},
+			oattr: &AttributeSchema{
+				Type:     cty.Number,
+				Optional: true,
+				Default:  1,
+			},
+			nattr: &AttributeSchema{
+				Type:     cty.Number,
+				Optional: true,
+				Default:  float64(1),
+			},
+			expect: nil,
+		},
+		{
+			name:  "Attribute default of map type unchanged",
+			scope: ResourceScope{Type: "foo_resource"},
+			path:  []string{"attr1"},
+			oattr: &AttributeSchema{
+				Type:     cty.Map(cty.String),
+				Optional: true,
+				Default:  map[string]interface{}{"a": "b"},
+			},
+			nattr: &AttributeSchema{
+				Type:     cty.Map(cty.String),
+				Optional: true,
+				Default:  map[string]interface{}{"a": "b"},
+			},
+			expect: nil,
+		},
+		{
+			name:  "Attribute default of list type updated",
+			scope: ResourceScope{Type: "foo_resource"},
+			path:  []string{"attr1"},
+			oattr: &AttributeSchema{
+				Type:     cty.List(cty.Number),
+				Optional: true,
+				Default:  []interface{}{1, 2},
+			},
+			nattr: &AttributeSchema{
+				Type:     cty.List(cty.Number),
+				Optional: true,
+				Default:  []interface{}{1},
+			},
+			expect: []Change{
+				AttributeChange{
+					Scope:    ResourceScope{Type: "foo_resource"},
+					Path:     []string{"attr1"},
+					IsModify: true,
+					Current: &Attribute{
+						Type:     cty.List(cty.Number),
+						Optional: true,
+						Default:  []interface{}{1},
+					},
+					Modification: &AttributeModify{
+						Default: &Modification[DefaultValue]{
+							From: newDefaultValue([]interface{}{1, 2}, cty.List(cty.Number)),
+							To:   newDefaultValue([]interface{}{1}, cty.List(cty.Number)),
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range cases {
@@ -137,20 +206,20 @@ func TestCompareNestedBlock(t *testing.T) {
 		name   string
 		scope  Scope
 		path   []string
-		oblk   *schema.NestedBlock
-		nblk   *schema.NestedBlock
+		oblk   *NestedBlockSchema
+		nblk   *NestedBlockSchema
 		expect []Change
 	}{
 		{
 			name:  "NestedBlock deleted",
 			scope: ResourceScope{Type: "foo_resource"},
 			path:  []string{"blk1"},
-			oblk: &schema.NestedBlock{
+			oblk: &NestedBlockSchema{
 				NestingMode: schema.NestingSingle,
 				Required:    true,
-				Block: &schema.Block{
-					Attributes:   map[string]*schema.Attribute{},
-					NestedBlocks: map[string]*schema.NestedBlock{},
+				Block: &BlockSchema{
+					Attributes:   map[string]*AttributeSchema{},
+					NestedBlocks: map[string]*NestedBlockSchema{},
 				},
 			},
 			nblk: nil,
@@ -159,6 +228,10 @@ func TestCompareNestedBlock(t *testing.T) {
 					Scope:    ResourceScope{Type: "foo_resource"},
 					Path:     []string{"blk1"},
 					IsDelete: true,
+					Previous: &Block{
+						NestingMode: schema.NestingSingle,
+						Required:    true,
+					},
 				},
 			},
 		},
@@ -167,12 +240,12 @@ func TestCompareNestedBlock(t *testing.T) {
 			scope: ResourceScope{Type: "foo_resource"},
 			path:  []string{"blk1"},
 			oblk:  nil,
-			nblk: &schema.NestedBlock{
+			nblk: &NestedBlockSchema{
 				NestingMode: schema.NestingSingle,
 				Required:    true,
-				Block: &schema.Block{
-					Attributes:   map[string]*schema.Attribute{},
-					NestedBlocks: map[string]*schema.NestedBlock{},
+				Block: &BlockSchema{
+					Attributes:   map[string]*AttributeSchema{},
+					NestedBlocks: map[string]*NestedBlockSchema{},
 				},
 			},
 			expect: []Change{
@@ -191,20 +264,20 @@ func TestCompareNestedBlock(t *testing.T) {
 			name:  "NestedBlock single updated",
 			scope: ResourceScope{Type: "foo_resource"},
 			path:  []string{"blk1"},
-			oblk: &schema.NestedBlock{
+			oblk: &NestedBlockSchema{
 				NestingMode: schema.NestingSingle,
 				Required:    true,
-				Block: &schema.Block{
-					Attributes:   map[string]*schema.Attribute{},
-					NestedBlocks: map[string]*schema.NestedBlock{},
+				Block: &BlockSchema{
+					Attributes:   map[string]*AttributeSchema{},
+					NestedBlocks: map[string]*NestedBlockSchema{},
 				},
 			},
-			nblk: &schema.NestedBlock{
+			nblk: &NestedBlockSchema{
 				NestingMode: schema.NestingGroup,
 				Required:    true,
-				Block: &schema.Block{
-					Attributes:   map[string]*schema.Attribute{},
-					NestedBlocks: map[string]*schema.NestedBlock{},
+				Block: &BlockSchema{
+					Attributes:   map[string]*AttributeSchema{},
+					NestedBlocks: map[string]*NestedBlockSchema{},
 				},
 			},
 			expect: []Change{
@@ -229,20 +302,20 @@ func TestCompareNestedBlock(t *testing.T) {
 			name:  "NestedBlock multiple updates",
 			scope: ResourceScope{Type: "foo_resource"},
 			path:  []string{"blk1"},
-			oblk: &schema.NestedBlock{
+			oblk: &NestedBlockSchema{
 				NestingMode: schema.NestingSingle,
 				Required:    true,
-				Block: &schema.Block{
-					Attributes:   map[string]*schema.Attribute{},
-					NestedBlocks: map[string]*schema.NestedBlock{},
+				Block: &BlockSchema{
+					Attributes:   map[string]*AttributeSchema{},
+					NestedBlocks: map[string]*NestedBlockSchema{},
 				},
 			},
-			nblk: &schema.NestedBlock{
+			nblk: &NestedBlockSchema{
 				NestingMode: schema.NestingGroup,
 				Optional:    true,
-				Block: &schema.Block{
-					Attributes:   map[string]*schema.Attribute{},
-					NestedBlocks: map[string]*schema.NestedBlock{},
+				Block: &BlockSchema{
+					Attributes:   map[string]*AttributeSchema{},
+					NestedBlocks: map[string]*NestedBlockSchema{},
 				},
 			},
 			expect: []Change{
@@ -285,16 +358,16 @@ func TestCompareBlock(t *testing.T) {
 		name   string
 		scope  Scope
 		path   []string
-		oblk   *schema.Block
-		nblk   *schema.Block
+		oblk   *BlockSchema
+		nblk   *BlockSchema
 		expect []Change
 	}{
 		{
 			name:  "Add/Delete/Modify attributes and nested blocks",
 			scope: ResourceScope{Type: "foo_resource"},
 			path:  []string{"blk"},
-			oblk: &schema.Block{
-				Attributes: map[string]*schema.Attribute{
+			oblk: &BlockSchema{
+				Attributes: map[string]*AttributeSchema{
 					"old_only_attr": {
 						Type: cty.Bool,
 					},
@@ -302,25 +375,25 @@ func TestCompareBlock(t *testing.T) {
 						Type: cty.Bool,
 					},
 				},
-				NestedBlocks: map[string]*schema.NestedBlock{
+				NestedBlocks: map[string]*NestedBlockSchema{
 					"old_only_blk": {
 						NestingMode: schema.NestingSingle,
-						Block: &schema.Block{
-							Attributes:   map[string]*schema.Attribute{},
-							NestedBlocks: map[string]*schema.NestedBlock{},
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
 						},
 					},
 					"update_blk": {
 						NestingMode: schema.NestingSingle,
-						Block: &schema.Block{
-							Attributes:   map[string]*schema.Attribute{},
-							NestedBlocks: map[string]*schema.NestedBlock{},
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
 						},
 					},
 				},
 			},
-			nblk: &schema.Block{
-				Attributes: map[string]*schema.Attribute{
+			nblk: &BlockSchema{
+				Attributes: map[string]*AttributeSchema{
 					"new_only_attr": {
 						Type: cty.Bool,
 					},
@@ -328,19 +401,19 @@ func TestCompareBlock(t *testing.T) {
 						Type: cty.String,
 					},
 				},
-				NestedBlocks: map[string]*schema.NestedBlock{
+				NestedBlocks: map[string]*NestedBlockSchema{
 					"new_only_blk": {
 						NestingMode: schema.NestingSingle,
-						Block: &schema.Block{
-							Attributes:   map[string]*schema.Attribute{},
-							NestedBlocks: map[string]*schema.NestedBlock{},
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
 						},
 					},
 					"update_blk": {
 						NestingMode: schema.NestingGroup,
-						Block: &schema.Block{
-							Attributes:   map[string]*schema.Attribute{},
-							NestedBlocks: map[string]*schema.NestedBlock{},
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
 						},
 					},
 				},
@@ -350,6 +423,9 @@ func TestCompareBlock(t *testing.T) {
 					Scope:    ResourceScope{Type: "foo_resource"},
 					Path:     []string{"blk", "old_only_attr"},
 					IsDelete: true,
+					Previous: &Attribute{
+						Type: cty.Bool,
+					},
 				},
 				AttributeChange{
 					Scope:    ResourceScope{Type: "foo_resource"},
@@ -377,6 +453,9 @@ func TestCompareBlock(t *testing.T) {
 					Scope:    ResourceScope{Type: "foo_resource"},
 					Path:     []string{"blk", "old_only_blk"},
 					IsDelete: true,
+					Previous: &Block{
+						NestingMode: schema.NestingSingle,
+					},
 				},
 				BlockChange{
 					Scope:    ResourceScope{Type: "foo_resource"},
@@ -402,6 +481,73 @@ func TestCompareBlock(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "Convert between attributes and nested blocks",
+			scope: ResourceScope{Type: "foo_resource"},
+			path:  []string{},
+			oblk: &BlockSchema{
+				Attributes: map[string]*AttributeSchema{
+					"to_blk": {
+						Type:     cty.List(cty.Object(map[string]cty.Type{"a": cty.String})),
+						Optional: true,
+					},
+				},
+				NestedBlocks: map[string]*NestedBlockSchema{
+					"to_attr": {
+						NestingMode: schema.NestingList,
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
+								"a": {Type: cty.String, Optional: true},
+							},
+						},
+					},
+				},
+			},
+			nblk: &BlockSchema{
+				Attributes: map[string]*AttributeSchema{
+					"to_attr": {
+						Type:     cty.List(cty.Object(map[string]cty.Type{"a": cty.String})),
+						Optional: true,
+					},
+				},
+				NestedBlocks: map[string]*NestedBlockSchema{
+					"to_blk": {
+						NestingMode: schema.NestingList,
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
+								"a": {Type: cty.String, Optional: true},
+							},
+						},
+					},
+				},
+			},
+			expect: []Change{
+				ConversionChange{
+					Scope:              ResourceScope{Type: "foo_resource"},
+					Path:               []string{"to_attr"},
+					IsBlockToAttribute: true,
+					PreviousBlock: &Block{
+						NestingMode: schema.NestingList,
+					},
+					CurrentAttribute: &Attribute{
+						Type:     cty.List(cty.Object(map[string]cty.Type{"a": cty.String})),
+						Optional: true,
+					},
+				},
+				ConversionChange{
+					Scope:              ResourceScope{Type: "foo_resource"},
+					Path:               []string{"to_blk"},
+					IsAttributeToBlock: true,
+					PreviousAttribute: &Attribute{
+						Type:     cty.List(cty.Object(map[string]cty.Type{"a": cty.String})),
+						Optional: true,
+					},
+					CurrentBlock: &Block{
+						NestingMode: schema.NestingList,
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range cases {
@@ -414,36 +560,37 @@ func TestCompareBlock(t *testing.T) {
 func TestCompareResources(t *testing.T) {
 	cases := []struct {
 		name   string
-		orm    map[string]*schema.Resource
-		nrm    map[string]*schema.Resource
+		orm    map[string]*ResourceSchema
+		nrm    map[string]*ResourceSchema
 		expect []Change
 	}{
 		{
 			name: "Resource deleted",
-			orm: map[string]*schema.Resource{
+			orm: map[string]*ResourceSchema{
 				"foo_resource": {
-					Block: &schema.Block{
-						Attributes:   map[string]*schema.Attribute{},
-						NestedBlocks: map[string]*schema.NestedBlock{},
+					Block: &BlockSchema{
+						Attributes:   map[string]*AttributeSchema{},
+						NestedBlocks: map[string]*NestedBlockSchema{},
 					},
 				},
 			},
-			nrm: map[string]*schema.Resource{},
+			nrm: map[string]*ResourceSchema{},
 			expect: []Change{
 				ResourceChange{
 					Type:     "foo_resource",
 					IsDelete: true,
+					Previous: &Resource{},
 				},
 			},
 		},
 		{
 			name: "Resource added",
-			orm:  map[string]*schema.Resource{},
-			nrm: map[string]*schema.Resource{
+			orm:  map[string]*ResourceSchema{},
+			nrm: map[string]*ResourceSchema{
 				"foo_resource": {
-					Block: &schema.Block{
-						Attributes:   map[string]*schema.Attribute{},
-						NestedBlocks: map[string]*schema.NestedBlock{},
+					Block: &BlockSchema{
+						Attributes:   map[string]*AttributeSchema{},
+						NestedBlocks: map[string]*NestedBlockSchema{},
 					},
 				},
 			},
@@ -457,20 +604,20 @@ func TestCompareResources(t *testing.T) {
 		},
 		{
 			name: "Resource update",
-			orm: map[string]*schema.Resource{
+			orm: map[string]*ResourceSchema{
 				"foo_resource": {
-					Block: &schema.Block{
-						Attributes:   map[string]*schema.Attribute{},
-						NestedBlocks: map[string]*schema.NestedBlock{},
+					Block: &BlockSchema{
+						Attributes:   map[string]*AttributeSchema{},
+						NestedBlocks: map[string]*NestedBlockSchema{},
 					},
 				},
 			},
-			nrm: map[string]*schema.Resource{
+			nrm: map[string]*ResourceSchema{
 				"foo_resource": {
 					SchemaVersion: 1,
-					Block: &schema.Block{
-						Attributes:   map[string]*schema.Attribute{},
-						NestedBlocks: map[string]*schema.NestedBlock{},
+					Block: &BlockSchema{
+						Attributes:   map[string]*AttributeSchema{},
+						NestedBlocks: map[string]*NestedBlockSchema{},
 					},
 				},
 			},
@@ -492,21 +639,21 @@ func TestCompareResources(t *testing.T) {
 		},
 		{
 			name: "Resource internal (attr/block) update",
-			orm: map[string]*schema.Resource{
+			orm: map[string]*ResourceSchema{
 				"foo_resource": {
-					Block: &schema.Block{
-						Attributes: map[string]*schema.Attribute{
+					Block: &BlockSchema{
+						Attributes: map[string]*AttributeSchema{
 							"old_only_attr": {},
 						},
-						NestedBlocks: map[string]*schema.NestedBlock{},
+						NestedBlocks: map[string]*NestedBlockSchema{},
 					},
 				},
 			},
-			nrm: map[string]*schema.Resource{
+			nrm: map[string]*ResourceSchema{
 				"foo_resource": {
-					Block: &schema.Block{
-						Attributes:   map[string]*schema.Attribute{},
-						NestedBlocks: map[string]*schema.NestedBlock{},
+					Block: &BlockSchema{
+						Attributes:   map[string]*AttributeSchema{},
+						NestedBlocks: map[string]*NestedBlockSchema{},
 					},
 				},
 			},
@@ -515,6 +662,7 @@ func TestCompareResources(t *testing.T) {
 					Scope:    ResourceScope{Type: "foo_resource"},
 					Path:     []string{"old_only_attr"},
 					IsDelete: true,
+					Previous: &Attribute{},
 				},
 			},
 		},
@@ -522,7 +670,7 @@ func TestCompareResources(t *testing.T) {
 
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
-			require.Equal(t, tt.expect, compareResources(tt.orm, tt.nrm, false))
+			require.Equal(t, tt.expect, compareResources(tt.orm, tt.nrm, false, false))
 		})
 	}
 }
@@ -530,21 +678,21 @@ func TestCompareResources(t *testing.T) {
 func TestCompare(t *testing.T) {
 	cases := []struct {
 		name   string
-		osch   *schema.ProviderSchema
-		nsch   *schema.ProviderSchema
+		osch   *ProviderSchema
+		nsch   *ProviderSchema
 		expect []Change
 	}{
 		{
 			name: "Provider config deleted (though not gonna happen in real life)",
-			osch: &schema.ProviderSchema{
-				Provider: &schema.Schema{
-					Block: &schema.Block{
-						Attributes:   map[string]*schema.Attribute{},
-						NestedBlocks: map[string]*schema.NestedBlock{},
+			osch: &ProviderSchema{
+				Provider: &ConfigSchema{
+					Block: &BlockSchema{
+						Attributes:   map[string]*AttributeSchema{},
+						NestedBlocks: map[string]*NestedBlockSchema{},
 					},
 				},
 			},
-			nsch: &schema.ProviderSchema{},
+			nsch: &ProviderSchema{},
 			expect: []Change{
 				ProviderChange{
 					IsDelete: true,
@@ -553,12 +701,12 @@ func TestCompare(t *testing.T) {
 		},
 		{
 			name: "Provider config added (though not gonna happen in real life)",
-			osch: &schema.ProviderSchema{},
-			nsch: &schema.ProviderSchema{
-				Provider: &schema.Schema{
-					Block: &schema.Block{
-						Attributes:   map[string]*schema.Attribute{},
-						NestedBlocks: map[string]*schema.NestedBlock{},
+			osch: &ProviderSchema{},
+			nsch: &ProviderSchema{
+				Provider: &ConfigSchema{
+					Block: &BlockSchema{
+						Attributes:   map[string]*AttributeSchema{},
+						NestedBlocks: map[string]*NestedBlockSchema{},
 					},
 				},
 			},
@@ -570,21 +718,21 @@ func TestCompare(t *testing.T) {
 		},
 		{
 			name: "Provider config attribute deleted",
-			osch: &schema.ProviderSchema{
-				Provider: &schema.Schema{
-					Block: &schema.Block{
-						Attributes: map[string]*schema.Attribute{
+			osch: &ProviderSchema{
+				Provider: &ConfigSchema{
+					Block: &BlockSchema{
+						Attributes: map[string]*AttributeSchema{
 							"old_only_attr": {},
 						},
-						NestedBlocks: map[string]*schema.NestedBlock{},
+						NestedBlocks: map[string]*NestedBlockSchema{},
 					},
 				},
 			},
-			nsch: &schema.ProviderSchema{
-				Provider: &schema.Schema{
-					Block: &schema.Block{
-						Attributes:   map[string]*schema.Attribute{},
-						NestedBlocks: map[string]*schema.NestedBlock{},
+			nsch: &ProviderSchema{
+				Provider: &ConfigSchema{
+					Block: &BlockSchema{
+						Attributes:   map[string]*AttributeSchema{},
+						NestedBlocks: map[string]*NestedBlockSchema{},
 					},
 				},
 			},
@@ -593,47 +741,48 @@ func TestCompare(t *testing.T) {
 					Scope:    ProviderScope{},
 					Path:     []string{"old_only_attr"},
 					IsDelete: true,
+					Previous: &Attribute{},
 				},
 			},
 		},
 		{
 			name: "Resource/DataSource attribute deleted",
-			osch: &schema.ProviderSchema{
-				DataSourceSchemas: map[string]*schema.Resource{
+			osch: &ProviderSchema{
+				DataSourceSchemas: map[string]*ResourceSchema{
 					"foo_resource": {
-						Block: &schema.Block{
-							Attributes: map[string]*schema.Attribute{
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
 								"old_only_attr": {},
 							},
-							NestedBlocks: map[string]*schema.NestedBlock{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
 						},
 					},
 				},
-				ResourceSchemas: map[string]*schema.Resource{
+				ResourceSchemas: map[string]*ResourceSchema{
 					"bar_resource": {
-						Block: &schema.Block{
-							Attributes: map[string]*schema.Attribute{
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
 								"old_only_attr": {},
 							},
-							NestedBlocks: map[string]*schema.NestedBlock{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
 						},
 					},
 				},
 			},
-			nsch: &schema.ProviderSchema{
-				DataSourceSchemas: map[string]*schema.Resource{
+			nsch: &ProviderSchema{
+				DataSourceSchemas: map[string]*ResourceSchema{
 					"foo_resource": {
-						Block: &schema.Block{
-							Attributes:   map[string]*schema.Attribute{},
-							NestedBlocks: map[string]*schema.NestedBlock{},
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
 						},
 					},
 				},
-				ResourceSchemas: map[string]*schema.Resource{
+				ResourceSchemas: map[string]*ResourceSchema{
 					"bar_resource": {
-						Block: &schema.Block{
-							Attributes:   map[string]*schema.Attribute{},
-							NestedBlocks: map[string]*schema.NestedBlock{},
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
 						},
 					},
 				},
@@ -643,11 +792,13 @@ func TestCompare(t *testing.T) {
 					Scope:    ResourceScope{Type: "foo_resource", IsDataSource: true},
 					Path:     []string{"old_only_attr"},
 					IsDelete: true,
+					Previous: &Attribute{},
 				},
 				AttributeChange{
 					Scope:    ResourceScope{Type: "bar_resource"},
 					Path:     []string{"old_only_attr"},
 					IsDelete: true,
+					Previous: &Attribute{},
 				},
 			},
 		},
@@ -4,17 +4,34 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
 
+	"github.com/open-policy-agent/opa/ast"
 	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/topdown"
 )
 
+// regoLib defines the helper functions that can be used in the rule expressions.
+const regoLib = `
+# schema_version_bumped tells whether the schema version of the resource of the scope is increased.
+schema_version_bumped(scope) {
+	some r in input.changes
+	r.kind == "resource"
+	r.type == scope.type
+	r.is_data_source == scope.is_data_source
+	r.is_ephemeral == scope.is_ephemeral
+	r.modification.schema_version.to > r.modification.schema_version.from
+}
+`
+
 func buildRegoModule(content string) string {
 	return fmt.Sprintf(`package provider
 
 import future.keywords.in
-
 %s
-`, content)
+%s
+`, regoLib, content)
 }
 
 func buildRule(content string) string {
@@ -26,15 +43,87 @@ breaking_change[i] {
 `, content)
 }
 
+type FilterOpt struct {
+	// SecretNamePatterns are the regexp patterns used to tell whether an attribute name looks like a secret.
+	// It is exposed to the rules as "input.secret_name_patterns". Defaults to DefaultSecretNamePatterns.
+	SecretNamePatterns []string
+
+	// Explain re-evaluates the rule with tracing enabled for each matched change, and records the explanation in the result.
+	Explain bool
+}
+
 type FilterResult struct {
 	Rule   string
 	Change Change
+
+	// Explanation is only set when FilterOpt.Explain is true and the change is matched by a rule.
+	Explanation *Explanation
+}
+
+// Explanation explains why a rule matched a change.
+type Explanation struct {
+	// Expr is the rule expression.
+	Expr string
+	// Input is the change (in JSON) that was evaluated.
+	Input string
+	// Trace is the condensed trace, which is the satisfied expressions (in the evaluation order) of the rule module.
+	Trace []string
+}
+
+func Filter(ctx context.Context, changes []Change, rules []Rule, opt FilterOpt) ([]FilterResult, error) {
+	prules, err := PrepareRules(ctx, rules)
+	if err != nil {
+		return nil, err
+	}
+	return prules.Filter(ctx, changes, opt)
+}
+
+// PreparedRules are the rules whose Rego queries are prepared, which can be used to filter multiple sets of changes
+// (e.g. of different schema versions) without recompiling the rules.
+type PreparedRules struct {
+	rules []preparedRule
+}
+
+type preparedRule struct {
+	Rule
+	module string
+	query  rego.PreparedEvalQuery
+}
+
+// PrepareRules prepares the Rego queries of the rules.
+func PrepareRules(ctx context.Context, rules []Rule) (*PreparedRules, error) {
+	prules := &PreparedRules{}
+	for _, rule := range rules {
+		module := buildRegoModule(buildRule(rule.Expr))
+		r := rego.New(
+			rego.Query("data.provider.breaking_change"),
+			rego.Module("rules", module))
+
+		query, err := r.PrepareForEval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		prules.rules = append(prules.rules, preparedRule{
+			Rule:   rule,
+			module: module,
+			query:  query,
+		})
+	}
+	return prules, nil
 }
 
-func Filter(ctx context.Context, changes []Change, rules []Rule) ([]FilterResult, error) {
+// Filter filters the changes by the prepared rules, see Filter.
+func (p *PreparedRules) Filter(ctx context.Context, changes []Change, opt FilterOpt) ([]FilterResult, error) {
+	// An invalid pattern makes the "regex.match" undefined in Rego, which silently fails the rule, hence it is validated beforehand.
+	for _, pattern := range opt.SecretNamePatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid secret name pattern %q: %v", pattern, err)
+		}
+	}
+
 	var results []FilterResult
 
-	if len(rules) == 0 {
+	if len(p.rules) == 0 {
 		for _, change := range changes {
 			results = append(results, FilterResult{
 				Change: change,
@@ -45,10 +134,15 @@ func Filter(ctx context.Context, changes []Change, rules []Rule) ([]FilterResult
 
 	// Turn the changes from an array to an object, as rego only process on json object as input.
 	type ChangeSet struct {
-		Changes []Change `json:"changes"`
+		Changes            []Change `json:"changes"`
+		SecretNamePatterns []string `json:"secret_name_patterns"`
 	}
 	cs := ChangeSet{
-		Changes: changes,
+		Changes:            changes,
+		SecretNamePatterns: opt.SecretNamePatterns,
+	}
+	if len(cs.SecretNamePatterns) == 0 {
+		cs.SecretNamePatterns = DefaultSecretNamePatterns
 	}
 
 	// Marshal and unmarshal back the change set to a Go map (default), which will then be able to be processd by rego.
@@ -64,16 +158,8 @@ func Filter(ctx context.Context, changes []Change, rules []Rule) ([]FilterResult
 	// maps the filtered change index to the filtering rule ID
 	used := map[int]string{}
 
-	for _, rule := range rules {
-		r := rego.New(
-			rego.Query("data.provider.breaking_change"),
-			rego.Module("rules", buildRegoModule(buildRule(rule.Expr))))
-
-		query, err := r.PrepareForEval(ctx)
-		if err != nil {
-			return nil, err
-		}
-		rs, err := query.Eval(ctx, rego.EvalInput(input))
+	for _, rule := range p.rules {
+		rs, err := rule.query.Eval(ctx, rego.EvalInput(input))
 		if err != nil {
 			return nil, err
 		}
@@ -83,13 +169,91 @@ func Filter(ctx context.Context, changes []Change, rules []Rule) ([]FilterResult
 			i := int(idx)
 			if _, ok := used[i]; !ok {
 				used[i] = rule.ID
-				results = append(results, FilterResult{
+				res := FilterResult{
 					Rule:   rule.ID,
 					Change: changes[idx],
-				})
+				}
+				if opt.Explain {
+					exp, err := explain(ctx, rule.module, input, i)
+					if err != nil {
+						return nil, fmt.Errorf("explaining rule %s: %v", rule.ID, err)
+					}
+					b, err := json.MarshalIndent(changes[idx], "", "  ")
+					if err != nil {
+						return nil, err
+					}
+					exp.Expr = rule.Expr
+					exp.Input = string(b)
+					res.Explanation = exp
+				}
+				results = append(results, res)
 			}
 		}
 	}
 
 	return results, nil
 }
+
+// explain evaluates the rule module against the change of the specified index with tracing enabled, and condenses the trace
+// to the satisfied expressions of the rule module.
+func explain(ctx context.Context, module string, input interface{}, idx int) (*Explanation, error) {
+	buf := topdown.NewBufferTracer()
+	r := rego.New(
+		rego.Query(fmt.Sprintf("data.provider.breaking_change[%d]", idx)),
+		rego.Module("rules", module),
+		rego.Input(input),
+		rego.QueryTracer(buf))
+	if _, err := r.Eval(ctx); err != nil {
+		return nil, err
+	}
+
+	type step struct {
+		queryID uint64
+		expr    *ast.Expr
+	}
+	var steps []step
+loop:
+	for _, event := range *buf {
+		if event.Location == nil || event.Location.File != "rules" {
+			continue
+		}
+		switch node := event.Node.(type) {
+		case *ast.Rule:
+			// Stop at the first success of the breaking_change rule, the expressions evaluated afterwards are for backtracking.
+			if event.Op == topdown.ExitOp && node.Head.Name.Equal(ast.Var("breaking_change")) {
+				break loop
+			}
+		case *ast.Expr:
+			switch event.Op {
+			case topdown.EvalOp:
+				steps = append(steps, step{event.QueryID, node})
+			case topdown.FailOp:
+				// Drop the failed expression, together with the expressions evaluated after it.
+				for i := len(steps) - 1; i >= 0; i-- {
+					if steps[i].queryID == event.QueryID && steps[i].expr == node {
+						steps = steps[:i]
+						break
+					}
+				}
+			}
+		}
+	}
+
+	exp := &Explanation{}
+	seen := map[string]bool{}
+	for _, step := range steps {
+		loc := step.expr.Location
+		// Skip the expressions generated by the compiler (e.g. for the function call arguments)
+		if loc == nil || step.expr.Generated {
+			continue
+		}
+		text := strings.TrimSpace(string(loc.Text))
+		key := fmt.Sprintf("%d:%d", loc.Row, loc.Col)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		exp.Trace = append(exp.Trace, text)
+	}
+	return exp, nil
+}
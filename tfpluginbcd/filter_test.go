@@ -5,14 +5,17 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestFilter(t *testing.T) {
 	cases := []struct {
-		name    string
-		changes []Change
-		rules   []Rule
-		expect  []FilterResult
+		name     string
+		changes  []Change
+		rules    []Rule
+		opt      FilterOpt
+		expect   []FilterResult
+		hasError bool
 	}{
 		{
 			name: "Resoruce is added",
@@ -47,13 +50,107 @@ c.is_add
 				},
 			},
 		},
+		{
+			name: "Secret name patterns",
+			changes: []Change{
+				AttributeChange{
+					Scope:   ResourceScope{Type: "foo_resource"},
+					Path:    []string{"admin_password"},
+					IsAdd:   true,
+					Current: &Attribute{Type: cty.String},
+				},
+				AttributeChange{
+					Scope:   ResourceScope{Type: "foo_resource"},
+					Path:    []string{"pin"},
+					IsAdd:   true,
+					Current: &Attribute{Type: cty.String},
+				},
+			},
+			rules: []Rule{Rules["S002"]},
+			opt: FilterOpt{
+				SecretNamePatterns: []string{"^pin$"},
+			},
+			expect: []FilterResult{
+				{
+					Rule: "S002",
+					Change: AttributeChange{
+						Scope:   ResourceScope{Type: "foo_resource"},
+						Path:    []string{"pin"},
+						IsAdd:   true,
+						Current: &Attribute{Type: cty.String},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid secret name pattern",
+			changes: []Change{
+				AttributeChange{
+					Scope:   ResourceScope{Type: "foo_resource"},
+					Path:    []string{"pass"},
+					IsAdd:   true,
+					Current: &Attribute{Type: cty.String},
+				},
+			},
+			rules: []Rule{Rules["S002"]},
+			opt: FilterOpt{
+				SecretNamePatterns: []string{"(pass"},
+			},
+			hasError: true,
+		},
 	}
 
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
-			actual, err := Filter(context.TODO(), tt.changes, tt.rules)
+			actual, err := Filter(context.TODO(), tt.changes, tt.rules, tt.opt)
+			if tt.hasError {
+				require.Error(t, err)
+				return
+			}
 			require.NoError(t, err)
 			require.Equal(t, tt.expect, actual)
 		})
 	}
 }
+
+func TestFilterExplain(t *testing.T) {
+	changes := []Change{
+		AttributeChange{
+			Scope:   ResourceScope{Type: "foo_resource"},
+			Path:    []string{"foo"},
+			IsAdd:   true,
+			Current: &Attribute{Type: cty.String},
+		},
+		AttributeChange{
+			Scope:    ResourceScope{Type: "foo_resource"},
+			Path:     []string{"bar"},
+			IsDelete: true,
+			Previous: &Attribute{Type: cty.String},
+		},
+	}
+	rules := []Rule{
+		{
+			ID: "CUSTOM",
+			Expr: `
+c.kind == "attribute"
+c.is_delete
+not schema_version_bumped(c.scope)
+`,
+		},
+	}
+	actual, err := Filter(context.TODO(), changes, rules, FilterOpt{Explain: true})
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Equal(t, changes[1], actual[0].Change)
+
+	exp := actual[0].Explanation
+	require.NotNil(t, exp)
+	require.Equal(t, rules[0].Expr, exp.Expr)
+	require.Contains(t, exp.Input, `"is_delete": true`)
+	require.Equal(t, []string{
+		"some i, c in input.changes",
+		`c.kind == "attribute"`,
+		"c.is_delete",
+		"not schema_version_bumped(c.scope)",
+	}, exp.Trace)
+}
@@ -0,0 +1,296 @@
+package tfpluginbcd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Function is the signature of a provider-defined function.
+type Function struct {
+	Parameters        []FunctionParameter `json:"parameters"`
+	VariadicParameter *FunctionParameter  `json:"variadic_parameter,omitempty"`
+	ReturnType        cty.Type            `json:"return_type"`
+	Docs
+}
+
+type FunctionParameter struct {
+	Name           string   `json:"name"`
+	Type           cty.Type `json:"type"`
+	AllowNullValue bool     `json:"allow_null_value"`
+}
+
+type FunctionModify struct {
+	ReturnType *Modification[cty.Type] `json:"return_type,omitempty"`
+	DocsModify
+}
+
+func (m FunctionModify) String() string {
+	return strings.Join(m.items(), ", ")
+}
+
+func (m FunctionModify) items() []string {
+	var l []string
+	if m.ReturnType != nil {
+		l = append(l, fmt.Sprintf("return type: %s -> %s", m.ReturnType.From.FriendlyName(), m.ReturnType.To.FriendlyName()))
+	}
+	l = append(l, m.DocsModify.items()...)
+	return l
+}
+
+type FunctionParameterModify struct {
+	Name           *Modification[string]   `json:"name,omitempty"`
+	Type           *Modification[cty.Type] `json:"type,omitempty"`
+	AllowNullValue *Modification[bool]     `json:"allow_null_value,omitempty"`
+}
+
+func (m FunctionParameterModify) String() string {
+	return strings.Join(m.items(), ", ")
+}
+
+func (m FunctionParameterModify) items() []string {
+	var l []string
+	if m.Name != nil {
+		l = append(l, fmt.Sprintf("name: %s -> %s", m.Name.From, m.Name.To))
+	}
+	if m.Type != nil {
+		l = append(l, fmt.Sprintf("type: %s -> %s", m.Type.From.FriendlyName(), m.Type.To.FriendlyName()))
+	}
+	if m.AllowNullValue != nil {
+		l = append(l, fmt.Sprintf("allow null value: %t -> %t", m.AllowNullValue.From, m.AllowNullValue.To))
+	}
+	return l
+}
+
+type FunctionChange struct {
+	// Function name
+	Name string `json:"name"`
+
+	// Exactly one of them is true
+	IsAdd    bool `json:"is_add"`
+	IsDelete bool `json:"is_delete"`
+	IsModify bool `json:"is_modify"`
+
+	// Current represents the current signature of this function, it is nil if IsDelete is true.
+	Current *Function `json:"current,omitempty"`
+
+	// Previous represents the previous signature of this function, it is non-nil only when IsDelete is true.
+	Previous *Function `json:"previous,omitempty"`
+
+	// Modification represents the modification of this function, it is non-nil only when IsModify is true.
+	Modification *FunctionModify `json:"modification,omitempty"`
+}
+
+func (FunctionChange) isChange() {}
+
+func (c FunctionChange) String() string {
+	msg := fmt.Sprintf("Function %q is", c.Name)
+
+	switch {
+	case c.IsAdd:
+		msg += " added"
+	case c.IsDelete:
+		msg += " deleted"
+	case c.IsModify:
+		msg += " changed: " + c.Modification.String()
+	}
+	return msg
+}
+
+func (c FunctionChange) MarshalJSON() ([]byte, error) {
+	type alias FunctionChange
+	return injectMarshal(alias(c), func(m map[string]interface{}) {
+		m["kind"] = ChangeKindFunction
+	})
+}
+
+// FunctionParameterChange is the change of a parameter of a function that exists in both schemas. The positional parameters are
+// compared by their positions, as the callers pass the arguments by position.
+type FunctionParameterChange struct {
+	// Function name
+	Function string `json:"function"`
+	// Index is the position of the parameter, which is always 0 for the variadic parameter.
+	Index      int  `json:"index"`
+	IsVariadic bool `json:"is_variadic"`
+
+	// Exactly one of them is true
+	IsAdd    bool `json:"is_add"`
+	IsDelete bool `json:"is_delete"`
+	IsModify bool `json:"is_modify"`
+
+	// Current represents the current parameter, it is nil if IsDelete is true.
+	Current *FunctionParameter `json:"current,omitempty"`
+
+	// Previous represents the previous parameter, it is non-nil only when IsDelete is true.
+	Previous *FunctionParameter `json:"previous,omitempty"`
+
+	// Modification represents the modification of this parameter, it is non-nil only when IsModify is true.
+	Modification *FunctionParameterModify `json:"modification,omitempty"`
+}
+
+func (FunctionParameterChange) isChange() {}
+
+func (c FunctionParameterChange) String() string {
+	param := c.Current
+	if param == nil {
+		param = c.Previous
+	}
+
+	var msg string
+	if c.IsVariadic {
+		msg += fmt.Sprintf("Variadic parameter %q", param.Name)
+	} else {
+		msg += fmt.Sprintf("Parameter %d (%q)", c.Index, param.Name)
+	}
+
+	msg += fmt.Sprintf(" of function %q is", c.Function)
+
+	switch {
+	case c.IsAdd:
+		msg += " added"
+	case c.IsDelete:
+		msg += " deleted"
+	case c.IsModify:
+		msg += " changed: " + c.Modification.String()
+	}
+	return msg
+}
+
+func (c FunctionParameterChange) MarshalJSON() ([]byte, error) {
+	type alias FunctionParameterChange
+	return injectMarshal(alias(c), func(m map[string]interface{}) {
+		m["kind"] = ChangeKindFunctionParameter
+	})
+}
+
+func NewFunctionModify(ofunc, nfunc Function) *FunctionModify {
+	isChanged := false
+	ret := &FunctionModify{}
+
+	if !ofunc.ReturnType.Equals(nfunc.ReturnType) {
+		isChanged = true
+		ret.ReturnType = &Modification[cty.Type]{
+			From: ofunc.ReturnType,
+			To:   nfunc.ReturnType,
+		}
+	}
+	if docsModification := NewDocsModify(ofunc.Docs, nfunc.Docs); docsModification != nil {
+		isChanged = true
+		ret.DocsModify = *docsModification
+	}
+
+	if !isChanged {
+		return nil
+	}
+	return ret
+}
+
+func NewFunctionParameterModify(oparam, nparam FunctionParameter) *FunctionParameterModify {
+	isChanged := false
+	ret := &FunctionParameterModify{}
+
+	if oparam.Name != nparam.Name {
+		isChanged = true
+		ret.Name = &Modification[string]{
+			From: oparam.Name,
+			To:   nparam.Name,
+		}
+	}
+	if !oparam.Type.Equals(nparam.Type) {
+		isChanged = true
+		ret.Type = &Modification[cty.Type]{
+			From: oparam.Type,
+			To:   nparam.Type,
+		}
+	}
+	if oparam.AllowNullValue != nparam.AllowNullValue {
+		isChanged = true
+		ret.AllowNullValue = &Modification[bool]{
+			From: oparam.AllowNullValue,
+			To:   nparam.AllowNullValue,
+		}
+	}
+
+	if !isChanged {
+		return nil
+	}
+	return ret
+}
+
+// compareFunctions compares the functions, where a null function entry is regarded as absent.
+func compareFunctions(ofm, nfm map[string]*Function) []Change {
+	var changes []Change
+	for _, name := range mapSortedKeys(ofm) {
+		ofunc := ofm[name]
+		if ofunc == nil {
+			continue
+		}
+		nfunc := nfm[name]
+		// Delete
+		if nfunc == nil {
+			changes = append(changes, FunctionChange{
+				Name:     name,
+				IsDelete: true,
+				Previous: ofunc,
+			})
+			continue
+		}
+		// Update
+		if modification := NewFunctionModify(*ofunc, *nfunc); modification != nil {
+			changes = append(changes, FunctionChange{
+				Name:         name,
+				IsModify:     true,
+				Current:      nfunc,
+				Modification: modification,
+			})
+		}
+		// Inner
+		for i := 0; i < len(ofunc.Parameters) || i < len(nfunc.Parameters); i++ {
+			var oparam, nparam *FunctionParameter
+			if i < len(ofunc.Parameters) {
+				oparam = &ofunc.Parameters[i]
+			}
+			if i < len(nfunc.Parameters) {
+				nparam = &nfunc.Parameters[i]
+			}
+			changes = append(changes, compareFunctionParameter(FunctionParameterChange{Function: name, Index: i}, oparam, nparam)...)
+		}
+		changes = append(changes, compareFunctionParameter(FunctionParameterChange{Function: name, IsVariadic: true}, ofunc.VariadicParameter, nfunc.VariadicParameter)...)
+	}
+
+	for _, name := range mapSortedKeys(nfm) {
+		// Add
+		if nfm[name] != nil && ofm[name] == nil {
+			changes = append(changes, FunctionChange{
+				Name:    name,
+				IsAdd:   true,
+				Current: nfm[name],
+			})
+		}
+	}
+	return changes
+}
+
+// compareFunctionParameter compares the parameters, whose change is based on the base change that identifies the parameter.
+func compareFunctionParameter(base FunctionParameterChange, oparam, nparam *FunctionParameter) []Change {
+	switch {
+	case oparam == nil && nparam == nil:
+		return nil
+	case nparam == nil:
+		base.IsDelete = true
+		base.Previous = oparam
+	case oparam == nil:
+		base.IsAdd = true
+		base.Current = nparam
+	default:
+		modification := NewFunctionParameterModify(*oparam, *nparam)
+		if modification == nil {
+			return nil
+		}
+		base.IsModify = true
+		base.Current = nparam
+		base.Modification = modification
+	}
+	return []Change{base}
+}
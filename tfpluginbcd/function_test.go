@@ -0,0 +1,158 @@
+package tfpluginbcd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestCompareFunctions(t *testing.T) {
+	cases := []struct {
+		name   string
+		ofm    map[string]*Function
+		nfm    map[string]*Function
+		expect []Change
+	}{
+		{
+			name: "Function deleted",
+			ofm: map[string]*Function{
+				"foo": {ReturnType: cty.String},
+			},
+			nfm: map[string]*Function{},
+			expect: []Change{
+				FunctionChange{
+					Name:     "foo",
+					IsDelete: true,
+					Previous: &Function{ReturnType: cty.String},
+				},
+			},
+		},
+		{
+			name: "Function added",
+			ofm:  map[string]*Function{},
+			nfm: map[string]*Function{
+				"foo": {ReturnType: cty.String},
+			},
+			expect: []Change{
+				FunctionChange{
+					Name:    "foo",
+					IsAdd:   true,
+					Current: &Function{ReturnType: cty.String},
+				},
+			},
+		},
+		{
+			name: "Null function entry",
+			ofm: map[string]*Function{
+				"foo": nil,
+				"bar": {ReturnType: cty.String},
+			},
+			nfm: map[string]*Function{
+				"foo": {ReturnType: cty.String},
+				"bar": nil,
+			},
+			expect: []Change{
+				FunctionChange{
+					Name:     "bar",
+					IsDelete: true,
+					Previous: &Function{ReturnType: cty.String},
+				},
+				FunctionChange{
+					Name:    "foo",
+					IsAdd:   true,
+					Current: &Function{ReturnType: cty.String},
+				},
+			},
+		},
+		{
+			name: "Function return type updated",
+			ofm: map[string]*Function{
+				"foo": {ReturnType: cty.String},
+			},
+			nfm: map[string]*Function{
+				"foo": {ReturnType: cty.Number},
+			},
+			expect: []Change{
+				FunctionChange{
+					Name:     "foo",
+					IsModify: true,
+					Current:  &Function{ReturnType: cty.Number},
+					Modification: &FunctionModify{
+						ReturnType: &Modification[cty.Type]{
+							From: cty.String,
+							To:   cty.Number,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Function parameters updated",
+			ofm: map[string]*Function{
+				"foo": {
+					Parameters: []FunctionParameter{
+						{Name: "a", Type: cty.String},
+						{Name: "b", Type: cty.String, AllowNullValue: true},
+					},
+					VariadicParameter: &FunctionParameter{Name: "rest", Type: cty.String},
+					ReturnType:        cty.String,
+				},
+			},
+			nfm: map[string]*Function{
+				"foo": {
+					Parameters: []FunctionParameter{
+						{Name: "a", Type: cty.Number},
+						{Name: "b", Type: cty.String},
+						{Name: "c", Type: cty.Bool},
+					},
+					ReturnType: cty.String,
+				},
+			},
+			expect: []Change{
+				FunctionParameterChange{
+					Function: "foo",
+					Index:    0,
+					IsModify: true,
+					Current:  &FunctionParameter{Name: "a", Type: cty.Number},
+					Modification: &FunctionParameterModify{
+						Type: &Modification[cty.Type]{
+							From: cty.String,
+							To:   cty.Number,
+						},
+					},
+				},
+				FunctionParameterChange{
+					Function: "foo",
+					Index:    1,
+					IsModify: true,
+					Current:  &FunctionParameter{Name: "b", Type: cty.String},
+					Modification: &FunctionParameterModify{
+						AllowNullValue: &Modification[bool]{
+							From: true,
+							To:   false,
+						},
+					},
+				},
+				FunctionParameterChange{
+					Function: "foo",
+					Index:    2,
+					IsAdd:    true,
+					Current:  &FunctionParameter{Name: "c", Type: cty.Bool},
+				},
+				FunctionParameterChange{
+					Function:   "foo",
+					IsVariadic: true,
+					IsDelete:   true,
+					Previous:   &FunctionParameter{Name: "rest", Type: cty.String},
+				},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expect, compareFunctions(tt.ofm, tt.nfm))
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package tfpluginbcd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+type HistoryOpt struct {
+	// Filter is a glob pattern (in syntax of path.Match) to filter the timelines by the address,
+	// which is in form of "provider[.<path>]", "provider_meta[.<path>]", "<type>[.<path>]", "data.<type>[.<path>]",
+	// "ephemeral.<type>[.<path>]", "identity.<type>[.<path>]" or "function.<name>[.<parameter>]".
+	// Note that "*" also matches ".", e.g. "foo.*" matches both "foo.bar" and "foo.bar.baz".
+	Filter string
+}
+
+// HistoryEvent is a change happened at a version.
+type HistoryEvent struct {
+	Version string
+	Change  Change
+}
+
+// History runs Compare across each consecutive pair of the ordered schema files, and shows the timeline for each changed object.
+// The paths can also be a single directory, where the schema files (*.json) are ordered by their versions (i.e. the file name without extension).
+func History(paths []string, opt HistoryOpt) (string, error) {
+	files, err := listSchemaFiles(paths)
+	if err != nil {
+		return "", err
+	}
+	var schemas []*ProviderSchema
+	for _, f := range files {
+		sch, err := loadSchema(f.Path)
+		if err != nil {
+			return "", err
+		}
+		schemas = append(schemas, sch)
+	}
+
+	timelines, err := history(files, schemas, opt.Filter)
+	if err != nil {
+		return "", err
+	}
+
+	var out []string
+	for _, addr := range mapSortedKeys(timelines) {
+		out = append(out, addr)
+		for _, ev := range timelines[addr] {
+			out = append(out, fmt.Sprintf("    %s: %s", ev.Version, changeSummary(ev.Change)))
+		}
+	}
+	return strings.Join(out, "\n"), nil
+}
+
+// history returns the timeline of each changed object, keyed by the address.
+func history(files []schemaFile, schemas []*ProviderSchema, filter string) (map[string][]HistoryEvent, error) {
+	if filter != "" {
+		if _, err := path.Match(filter, ""); err != nil {
+			return nil, fmt.Errorf("invalid filter %q: %v", filter, err)
+		}
+	}
+	timelines := map[string][]HistoryEvent{}
+	for i := 1; i < len(schemas); i++ {
+		for _, change := range Compare(schemas[i-1], schemas[i]) {
+			addr := changeAddress(change)
+			if filter != "" {
+				if ok, _ := path.Match(filter, addr); !ok {
+					continue
+				}
+			}
+			timelines[addr] = append(timelines[addr], HistoryEvent{
+				Version: files[i].Version,
+				Change:  change,
+			})
+		}
+	}
+	return timelines, nil
+}
+
+// changeAddress returns the Terraform address alike string of the changed object,
+// which is in form of "provider[.<path>]", "provider_meta[.<path>]", "<type>[.<path>]", "data.<type>[.<path>]",
+// "ephemeral.<type>[.<path>]", "identity.<type>[.<path>]" or "function.<name>[.<parameter>]".
+func changeAddress(change Change) string {
+	switch change := change.(type) {
+	case ResourceChange:
+		return scopeAddress(ResourceScope{Type: change.Type, IsDataSource: change.IsDataSource, IsEphemeral: change.IsEphemeral})
+	case AttributeChange:
+		return strings.Join(append([]string{scopeAddress(change.Scope)}, change.Path...), ".")
+	case BlockChange:
+		return strings.Join(append([]string{scopeAddress(change.Scope)}, change.Path...), ".")
+	case ConversionChange:
+		return strings.Join(append([]string{scopeAddress(change.Scope)}, change.Path...), ".")
+	case FunctionChange:
+		return "function." + change.Name
+	case FunctionParameterChange:
+		param := change.Current
+		if param == nil {
+			param = change.Previous
+		}
+		return "function." + change.Function + "." + param.Name
+	}
+	return "provider"
+}
+
+// changeSummary returns a brief description of the change, without mentioning the changed object.
+func changeSummary(change Change) string {
+	var (
+		verb  string
+		items []string
+	)
+	switch change := change.(type) {
+	case ProviderChange:
+		verb = changeVerb(change.IsAdd, change.IsDelete, false)
+	case ResourceChange:
+		verb = changeVerb(change.IsAdd, change.IsDelete, change.IsModify)
+		if change.Modification != nil {
+			items = change.Modification.items()
+		}
+	case AttributeChange:
+		verb = changeVerb(change.IsAdd, change.IsDelete, change.IsModify)
+		if change.Modification != nil {
+			items = change.Modification.items()
+		}
+	case BlockChange:
+		verb = changeVerb(change.IsAdd, change.IsDelete, change.IsModify)
+		if change.Modification != nil {
+			items = change.Modification.items()
+		}
+	case ConversionChange:
+		verb = change.verb()
+	case FunctionChange:
+		verb = changeVerb(change.IsAdd, change.IsDelete, change.IsModify)
+		if change.Modification != nil {
+			items = change.Modification.items()
+		}
+	case FunctionParameterChange:
+		verb = changeVerb(change.IsAdd, change.IsDelete, change.IsModify)
+		if change.Modification != nil {
+			items = change.Modification.items()
+		}
+	}
+	if len(items) == 0 {
+		return verb
+	}
+	return verb + ": " + strings.Join(items, ", ")
+}
+
+type schemaFile struct {
+	Version string
+	Path    string
+}
+
+// listSchemaFiles returns the ordered schema files. The paths are either the ordered schema files, or a single directory
+// containing the schema files (*.json), which are ordered by their versions (i.e. the file name without extension).
+// For the schema files, the versions are the file names without extension as well, unless they are not unique (e.g.
+// "a/schema.json" and "b/schema.json"), where the paths are used instead.
+func listSchemaFiles(paths []string) ([]schemaFile, error) {
+	if len(paths) == 1 {
+		fi, err := os.Stat(paths[0])
+		if err != nil {
+			return nil, err
+		}
+		if fi.IsDir() {
+			return listSchemaDir(paths[0])
+		}
+	}
+	if len(paths) < 2 {
+		return nil, fmt.Errorf("expect at least two schema files")
+	}
+	var files []schemaFile
+	versions := map[string]bool{}
+	isUnique := true
+	for _, p := range paths {
+		version := strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+		if versions[version] {
+			isUnique = false
+		}
+		versions[version] = true
+		files = append(files, schemaFile{
+			Version: version,
+			Path:    p,
+		})
+	}
+	if !isUnique {
+		for i := range files {
+			files[i].Version = files[i].Path
+		}
+	}
+	return files, nil
+}
+
+func listSchemaDir(dir string) ([]schemaFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []schemaFile
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		files = append(files, schemaFile{
+			Version: strings.TrimSuffix(entry.Name(), ".json"),
+			Path:    filepath.Join(dir, entry.Name()),
+		})
+	}
+	if len(files) < 2 {
+		return nil, fmt.Errorf("expect at least two schema files under %s", dir)
+	}
+	slices.SortStableFunc(files, func(a, b schemaFile) bool {
+		return versionLess(a.Version, b.Version)
+	})
+	return files, nil
+}
+
+// versionLess orders the valid semantic versions semantically, before the invalid ones, which are ordered lexically.
+// Keeping both groups apart makes the order transitive, as required by sorting.
+func versionLess(a, b string) bool {
+	av, aerr := parseVersion(a)
+	bv, berr := parseVersion(b)
+	if (aerr == nil) != (berr == nil) {
+		return aerr == nil
+	}
+	if aerr != nil {
+		return a < b
+	}
+	for i := range av {
+		if av[i] != bv[i] {
+			return av[i] < bv[i]
+		}
+	}
+	return a < b
+}
@@ -0,0 +1,110 @@
+package tfpluginbcd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/exp/slices"
+)
+
+func TestHistory(t *testing.T) {
+	resourceSchema := func(attrs map[string]*AttributeSchema) *ProviderSchema {
+		return &ProviderSchema{
+			ResourceSchemas: map[string]*ResourceSchema{
+				"foo_resource": {
+					Block: &BlockSchema{
+						Attributes:   attrs,
+						NestedBlocks: map[string]*NestedBlockSchema{},
+					},
+				},
+			},
+		}
+	}
+	files := []schemaFile{{Version: "v1"}, {Version: "v2"}, {Version: "v3"}, {Version: "v4"}}
+	schemas := []*ProviderSchema{
+		{},
+		resourceSchema(map[string]*AttributeSchema{}),
+		resourceSchema(map[string]*AttributeSchema{
+			"attr": {Type: cty.String, Optional: true},
+		}),
+		resourceSchema(map[string]*AttributeSchema{
+			"attr": {Type: cty.String, Optional: true, ForceNew: true},
+		}),
+	}
+
+	cases := []struct {
+		name   string
+		filter string
+		expect map[string][]string
+	}{
+		{
+			name: "all",
+			expect: map[string][]string{
+				"foo_resource":      {"v2: added"},
+				"foo_resource.attr": {"v3: added", "v4: changed: force new: false -> true"},
+			},
+		},
+		{
+			name:   "filtered",
+			filter: "foo_resource.*",
+			expect: map[string][]string{
+				"foo_resource.attr": {"v3: added", "v4: changed: force new: false -> true"},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			timelines, err := history(files, schemas, tt.filter)
+			require.NoError(t, err)
+			actual := map[string][]string{}
+			for addr, events := range timelines {
+				for _, ev := range events {
+					actual[addr] = append(actual[addr], ev.Version+": "+changeSummary(ev.Change))
+				}
+			}
+			require.Equal(t, tt.expect, actual)
+		})
+	}
+}
+
+func TestListSchemaFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"v1.10.0.json", "v1.9.0.json", "v1.2.0.json", "README.md"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644))
+	}
+	files, err := listSchemaFiles([]string{dir})
+	require.NoError(t, err)
+	var versions []string
+	for _, f := range files {
+		versions = append(versions, f.Version)
+	}
+	require.Equal(t, []string{"v1.2.0", "v1.9.0", "v1.10.0"}, versions)
+
+	files, err = listSchemaFiles([]string{"v1.json", "v2.json"})
+	require.NoError(t, err)
+	require.Equal(t, []schemaFile{{Version: "v1", Path: "v1.json"}, {Version: "v2", Path: "v2.json"}}, files)
+
+	// The paths are used as the versions, as the file names are not unique
+	files, err = listSchemaFiles([]string{"a/schema.json", "b/schema.json", "c/other.json"})
+	require.NoError(t, err)
+	require.Equal(t, []schemaFile{
+		{Version: "a/schema.json", Path: "a/schema.json"},
+		{Version: "b/schema.json", Path: "b/schema.json"},
+		{Version: "c/other.json", Path: "c/other.json"},
+	}, files)
+}
+
+func TestVersionLess(t *testing.T) {
+	versions := []string{"next", "v1.10.0", "latest", "v1.9.0", "1.2.0", "beta"}
+	slices.SortFunc(versions, versionLess)
+	require.Equal(t, []string{"1.2.0", "v1.9.0", "v1.10.0", "beta", "latest", "next"}, versions)
+
+	// Mixing the valid and invalid versions keeps the order transitive.
+	require.True(t, versionLess("v1.10.0", "v1.9.x"))
+	require.True(t, versionLess("v1.9.0", "v1.10.0"))
+	require.False(t, versionLess("v1.9.x", "v1.9.0"))
+}
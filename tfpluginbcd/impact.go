@@ -0,0 +1,622 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/magodo/tfpluginschema/schema"
+	"golang.org/x/exp/slices"
+)
+
+type ImpactOpt struct {
+	// Opt selects the rules of the breaking changes. Defaults to the "ga" profile if no rule is selected.
+	Opt
+
+	// ProviderName is the local name of the provider, used to match the provider blocks for the changes of the provider config.
+	// Defaults to the prefix of the resource types (e.g. "azurerm" for "azurerm_resource_group").
+	ProviderName string
+}
+
+// ImpactUsage is a usage in the Terraform configuration that is affected by a breaking change.
+type ImpactUsage struct {
+	Rule   string
+	Change Change
+
+	// Address is the address of the affected resource or data source block (e.g. "foo_resource.x", "data.foo_data_source.x"),
+	// or "provider.<name>" for the provider block. For the references, it is the address of the referenced resource or data source.
+	Address string
+
+	// Range is the source range of the affected usage. For the added attributes or blocks, it is the range of the enclosing block
+	// that doesn't set them. For the references, it is the range of the traversal. Otherwise, it is the range of the attribute or
+	// block that sets them.
+	Range hcl.Range
+
+	// Reference is the traversal (e.g. "foo_resource.x.attr") in an expression that references the deleted or type changed
+	// attribute (or the deleted block). It is empty if the usage is not a reference.
+	Reference string
+}
+
+func (u ImpactUsage) String() string {
+	msg := fmt.Sprintf("%s: %s: [%s] %s", u.Range.String(), u.Address, u.Rule, u.Change.String())
+	if u.Reference != "" {
+		msg += fmt.Sprintf(" (referenced as %s)", u.Reference)
+	}
+	return msg
+}
+
+// Impact cross-references the breaking changes against the Terraform configuration files (*.tf) under the directory (recursively),
+// and shows the affected usages, including the references to the deleted or type changed attributes in the expressions.
+func Impact(ctx context.Context, opath, npath, dir string, opt ImpactOpt) (string, error) {
+	osch, nsch, err := loadSchemas(opath, npath)
+	if err != nil {
+		return "", err
+	}
+	files, err := loadConfigFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	usages, err := impact(ctx, *osch, *nsch, files, opt)
+	if err != nil {
+		return "", err
+	}
+	var out []string
+	for _, u := range usages {
+		out = append(out, u.String())
+	}
+	return strings.Join(out, "\n"), nil
+}
+
+// loadConfigFiles parses the Terraform configuration files (*.tf) under the directory recursively, ordered by the file paths.
+func loadConfigFiles(dir string) ([]*hcl.File, error) {
+	paths, err := listConfigFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []*hcl.File
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading the configuration file %s: %v", path, err)
+		}
+		f, diags := hclsyntax.ParseConfig(b, path, hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("parsing the configuration file %s: %v", path, diags.Error())
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// listConfigFiles lists the Terraform configuration files (*.tf) under the directory recursively (except the .terraform directories),
+// ordered by the file paths.
+func listConfigFiles(dir string) ([]string, error) {
+	var paths []string
+	if err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".terraform" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) == ".tf" {
+			paths = append(paths, path)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("walking the directory %s: %v", dir, err)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func impact(ctx context.Context, osch, nsch ProviderSchema, files []*hcl.File, opt ImpactOpt) ([]ImpactUsage, error) {
+	if !opt.hasRules() {
+		opt.Profile = "ga"
+	}
+	rules, err := buildRules(opt.Opt)
+	if err != nil {
+		return nil, err
+	}
+	results, err := Filter(ctx, Compare(&osch, &nsch), rules, opt.filterOpt())
+	if err != nil {
+		return nil, fmt.Errorf("filtering: %v", err)
+	}
+
+	providerName := opt.ProviderName
+	if providerName == "" {
+		providerName = providerLocalName(&nsch)
+	}
+
+	var usages []ImpactUsage
+	for _, f := range files {
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, blk := range body.Blocks {
+			scope, addr, ok := configBlockScope(blk.Type, blk.Labels, providerName)
+			if !ok {
+				continue
+			}
+			for _, res := range results {
+				var (
+					cscope Scope
+					path   []string
+					isAdd  bool
+				)
+				switch change := res.Change.(type) {
+				case AttributeChange:
+					cscope, path, isAdd = change.Scope, change.Path, change.IsAdd
+				case BlockChange:
+					cscope, path, isAdd = change.Scope, change.Path, change.IsAdd
+				case ConversionChange:
+					// The usages in the previous syntax are affected
+					cscope, path = change.Scope, change.Path
+				default:
+					continue
+				}
+				if cscope != scope {
+					continue
+				}
+				// The added paths are looked up in the new schema, while the others in the old schema.
+				sch := &osch
+				if isAdd {
+					sch = &nsch
+				}
+				for _, rng := range configUsageRanges(blk.Body, sch.scopeBlock(scope), path, isAdd) {
+					usages = append(usages, ImpactUsage{
+						Rule:    res.Rule,
+						Change:  res.Change,
+						Address: addr,
+						Range:   rng,
+					})
+				}
+			}
+		}
+		usages = append(usages, configReferenceUsages(body, results)...)
+	}
+	return usages, nil
+}
+
+// exprTraversal is a traversal in the expression, together with its source range. For the traversals of the for expression
+// iterators, the traversal is resolved to the one of the collection (e.g. "r.ip" in "[for r in x.rules : r.ip]" is resolved
+// to "x.rules[*].ip"), while the range is the one of the iterator traversal.
+type exprTraversal struct {
+	traversal hcl.Traversal
+	rng       hcl.Range
+}
+
+// configReferenceUsages returns the usages of the traversals in the expressions of the body (in source order), which reference
+// the deleted or type changed attributes, or the deleted blocks.
+func configReferenceUsages(body *hclsyntax.Body, results []FilterResult) []ImpactUsage {
+	var traversals []exprTraversal
+	var walk func(body *hclsyntax.Body)
+	walk = func(body *hclsyntax.Body) {
+		for _, attr := range body.Attributes {
+			chains := chainTraversals(attr.Expr)
+			starts := map[int]bool{}
+			for _, chain := range chains {
+				starts[chain.rng.Start.Byte] = true
+			}
+			for _, traversal := range attr.Expr.Variables() {
+				// The traversals extended by the index, splat or relative traversal expressions are replaced by the chains
+				if rng := traversal.SourceRange(); !starts[rng.Start.Byte] {
+					traversals = append(traversals, exprTraversal{traversal: traversal, rng: rng})
+				}
+			}
+			traversals = append(traversals, chains...)
+			traversals = append(traversals, iteratorTraversals(attr.Expr)...)
+		}
+		for _, blk := range body.Blocks {
+			walk(blk.Body)
+		}
+	}
+	walk(body)
+	sort.SliceStable(traversals, func(i, j int) bool {
+		return traversals[i].rng.Start.Byte < traversals[j].rng.Start.Byte
+	})
+
+	var usages []ImpactUsage
+	for _, et := range traversals {
+		traversal := et.traversal
+		scope, addr, path, ok := traversalReference(traversal)
+		if !ok {
+			continue
+		}
+		for _, res := range results {
+			var (
+				cscope Scope
+				cpath  []string
+			)
+			switch change := res.Change.(type) {
+			case AttributeChange:
+				if !change.IsDelete && !(change.IsModify && change.Modification.Type != nil) {
+					continue
+				}
+				cscope, cpath = change.Scope, change.Path
+			case BlockChange:
+				if !change.IsDelete {
+					continue
+				}
+				cscope, cpath = change.Scope, change.Path
+			default:
+				continue
+			}
+			if cscope != scope || len(cpath) > len(path) || !slices.Equal(cpath, path[:len(cpath)]) {
+				continue
+			}
+			usages = append(usages, ImpactUsage{
+				Rule:      res.Rule,
+				Change:    res.Change,
+				Address:   addr,
+				Range:     et.rng,
+				Reference: traversalString(traversal),
+			})
+		}
+	}
+	return usages
+}
+
+// chainTraversals returns the traversals that are extended by the index, splat or relative traversal expressions in the expression,
+// e.g. "x.na[count.index].attr" or "x.na[*].attr", whose dynamic indexes are regarded as splats.
+func chainTraversals(expr hclsyntax.Expression) []exprTraversal {
+	w := &chainWalker{inner: map[hclsyntax.Node]bool{}}
+	hclsyntax.Walk(expr, w)
+	return w.traversals
+}
+
+type chainWalker struct {
+	// inner are the inner expressions of the chains that have been resolved.
+	inner      map[hclsyntax.Node]bool
+	traversals []exprTraversal
+}
+
+func (w *chainWalker) Enter(node hclsyntax.Node) hcl.Diagnostics {
+	switch node.(type) {
+	case *hclsyntax.RelativeTraversalExpr, *hclsyntax.IndexExpr, *hclsyntax.SplatExpr:
+		if w.inner[node] {
+			return nil
+		}
+		expr := node.(hclsyntax.Expression)
+		if traversal, ok := w.resolve(expr); ok {
+			w.traversals = append(w.traversals, exprTraversal{traversal: traversal, rng: expr.Range()})
+		}
+	}
+	return nil
+}
+
+func (w *chainWalker) Exit(node hclsyntax.Node) hcl.Diagnostics {
+	return nil
+}
+
+// resolve returns the traversal of the chain expression rooted at a scope traversal, with the inner expressions marked.
+func (w *chainWalker) resolve(expr hclsyntax.Expression) (hcl.Traversal, bool) {
+	w.inner[expr] = true
+	switch expr := expr.(type) {
+	case *hclsyntax.ScopeTraversalExpr:
+		return append(hcl.Traversal{}, expr.Traversal...), true
+	case *hclsyntax.RelativeTraversalExpr:
+		src, ok := w.resolve(expr.Source)
+		if !ok {
+			return nil, false
+		}
+		return append(src, expr.Traversal...), true
+	case *hclsyntax.IndexExpr:
+		src, ok := w.resolve(expr.Collection)
+		if !ok {
+			return nil, false
+		}
+		if key, ok := expr.Key.(*hclsyntax.LiteralValueExpr); ok {
+			return append(src, hcl.TraverseIndex{Key: key.Val}), true
+		}
+		return append(src, hcl.TraverseSplat{}), true
+	case *hclsyntax.SplatExpr:
+		src, ok := w.resolve(expr.Source)
+		if !ok {
+			return nil, false
+		}
+		src = append(src, hcl.TraverseSplat{})
+		if each, ok := expr.Each.(*hclsyntax.RelativeTraversalExpr); ok && each.Source == expr.Item {
+			src = append(src, each.Traversal...)
+		}
+		return src, true
+	}
+	return nil, false
+}
+
+// iteratorTraversals returns the traversals of the for expression iterators in the expression, which are resolved to the
+// traversals of their collections, see exprTraversal. The traversals not rooted at the iterators are not included.
+func iteratorTraversals(expr hclsyntax.Expression) []exprTraversal {
+	w := &iteratorWalker{colls: map[hclsyntax.Expression]bool{}}
+	hclsyntax.Walk(expr, w)
+	return w.traversals
+}
+
+type iteratorWalker struct {
+	// scopes is the stack of the iterator symbols of the enclosing for expressions, mapped to the traversals of their collections.
+	// The key symbols, and the value symbols whose collections are not traversals, are mapped to nil.
+	scopes []map[string]hcl.Traversal
+	// colls are the collection expressions of the for expressions, which are resolved against the outer scopes on entering the for expressions.
+	colls      map[hclsyntax.Expression]bool
+	traversals []exprTraversal
+}
+
+// resolve resolves the traversal rooted at an iterator symbol to the traversal of its collection. It returns false if the traversal isn't
+// rooted at an iterator symbol, or nil if the collection of the symbol isn't a traversal.
+func (w *iteratorWalker) resolve(traversal hcl.Traversal) (hcl.Traversal, bool) {
+	for i := len(w.scopes) - 1; i >= 0; i-- {
+		coll, ok := w.scopes[i][traversal.RootName()]
+		if !ok {
+			continue
+		}
+		if coll == nil {
+			return nil, true
+		}
+		out := append(append(hcl.Traversal{}, coll...), hcl.TraverseSplat{})
+		return append(out, traversal[1:]...), true
+	}
+	return nil, false
+}
+
+func (w *iteratorWalker) Enter(node hclsyntax.Node) hcl.Diagnostics {
+	switch node := node.(type) {
+	case *hclsyntax.ForExpr:
+		var coll hcl.Traversal
+		if expr, ok := node.CollExpr.(*hclsyntax.ScopeTraversalExpr); ok {
+			w.colls[expr] = true
+			coll = expr.Traversal
+			if resolved, ok := w.resolve(expr.Traversal); ok {
+				coll = resolved
+				if resolved != nil {
+					w.traversals = append(w.traversals, exprTraversal{traversal: resolved, rng: expr.SrcRange})
+				}
+			}
+		}
+		scope := map[string]hcl.Traversal{node.ValVar: coll}
+		if node.KeyVar != "" {
+			scope[node.KeyVar] = nil
+		}
+		w.scopes = append(w.scopes, scope)
+	case *hclsyntax.ScopeTraversalExpr:
+		if w.colls[node] {
+			return nil
+		}
+		if resolved, ok := w.resolve(node.Traversal); ok && resolved != nil {
+			w.traversals = append(w.traversals, exprTraversal{traversal: resolved, rng: node.SrcRange})
+		}
+	}
+	return nil
+}
+
+func (w *iteratorWalker) Exit(node hclsyntax.Node) hcl.Diagnostics {
+	if _, ok := node.(*hclsyntax.ForExpr); ok {
+		w.scopes = w.scopes[:len(w.scopes)-1]
+	}
+	return nil
+}
+
+// traversalReference returns the scope, the address and the attribute path (with the indexes removed) of the resource,
+// data source or ephemeral resource referenced by the traversal, e.g. "data.foo_data_source.x[0].blk[0].attr".
+func traversalReference(traversal hcl.Traversal) (Scope, string, []string, bool) {
+	var names []string
+	var path []string
+	for _, step := range traversal {
+		switch step := step.(type) {
+		case hcl.TraverseRoot:
+			names = append(names, step.Name)
+		case hcl.TraverseAttr:
+			if len(names) < 2 || ((names[0] == "data" || names[0] == "ephemeral") && len(names) < 3) {
+				names = append(names, step.Name)
+			} else {
+				path = append(path, step.Name)
+			}
+		case hcl.TraverseIndex, hcl.TraverseSplat:
+			// The indexes of the resources (count/for_each) and the nested blocks are ignored
+		default:
+			return nil, "", nil, false
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, "", nil, false
+	}
+	switch names[0] {
+	case "var", "local", "module", "each", "count", "path", "terraform", "self":
+		return nil, "", nil, false
+	case "data":
+		if len(names) != 3 || len(path) == 0 {
+			return nil, "", nil, false
+		}
+		return ResourceScope{Type: names[1], IsDataSource: true}, strings.Join(names, "."), path, true
+	case "ephemeral":
+		if len(names) != 3 || len(path) == 0 {
+			return nil, "", nil, false
+		}
+		return ResourceScope{Type: names[1], IsEphemeral: true}, strings.Join(names, "."), path, true
+	default:
+		if len(names) != 2 || len(path) == 0 {
+			return nil, "", nil, false
+		}
+		return ResourceScope{Type: names[0]}, strings.Join(names, "."), path, true
+	}
+}
+
+// traversalString returns the source form of the traversal, e.g. `foo_resource.x["a"].attr`.
+func traversalString(traversal hcl.Traversal) string {
+	var out string
+	for _, step := range traversal {
+		switch step := step.(type) {
+		case hcl.TraverseRoot:
+			out += step.Name
+		case hcl.TraverseAttr:
+			out += "." + step.Name
+		case hcl.TraverseIndex:
+			out += "[" + formatCtyValue(step.Key) + "]"
+		case hcl.TraverseSplat:
+			out += "[*]"
+		}
+	}
+	return out
+}
+
+// providerLocalName returns the prefix of the resource types (or the data source types) of the schema.
+func providerLocalName(sch *ProviderSchema) string {
+	for _, m := range []map[string]*ResourceSchema{sch.ResourceSchemas, sch.DataSourceSchemas} {
+		for _, rt := range mapSortedKeys(m) {
+			return strings.SplitN(rt, "_", 2)[0]
+		}
+	}
+	return ""
+}
+
+// configBlockScope returns the scope and the address of the top level resource, data, ephemeral or provider block.
+func configBlockScope(typ string, labels []string, providerName string) (Scope, string, bool) {
+	switch typ {
+	case "resource":
+		if len(labels) != 2 {
+			return nil, "", false
+		}
+		return ResourceScope{Type: labels[0]}, labels[0] + "." + labels[1], true
+	case "data":
+		if len(labels) != 2 {
+			return nil, "", false
+		}
+		return ResourceScope{Type: labels[0], IsDataSource: true}, "data." + labels[0] + "." + labels[1], true
+	case "ephemeral":
+		if len(labels) != 2 {
+			return nil, "", false
+		}
+		return ResourceScope{Type: labels[0], IsEphemeral: true}, "ephemeral." + labels[0] + "." + labels[1], true
+	case "provider":
+		if len(labels) != 1 || labels[0] != providerName {
+			return nil, "", false
+		}
+		return ProviderScope{}, "provider." + providerName, true
+	}
+	return nil, "", false
+}
+
+// configUsageRanges returns the ranges of the attributes or blocks of the path that are set in the body, where "blk" is the schema
+// of the body. The paths into the nested attributes are matched against the object and tuple constructors assigned to them.
+// If isAdd is true, it returns the ranges of the enclosing blocks (or objects) that don't set the path instead.
+func configUsageRanges(body *hclsyntax.Body, blk *BlockSchema, path []string, isAdd bool) []hcl.Range {
+	if len(path) == 0 {
+		return nil
+	}
+	name := path[0]
+	if len(path) == 1 {
+		_, hasAttr := body.Attributes[name]
+		blks := configNestedBodies(body, name)
+		if isAdd {
+			if hasAttr || len(blks) != 0 {
+				return nil
+			}
+			return []hcl.Range{body.SrcRange}
+		}
+		var ranges []hcl.Range
+		if attr, ok := body.Attributes[name]; ok {
+			ranges = append(ranges, attr.SrcRange)
+		}
+		for _, blk := range blks {
+			ranges = append(ranges, blk.SrcRange)
+		}
+		return ranges
+	}
+	var ranges []hcl.Range
+	attrSch, nestedBlkSch := blk.lookupMember(path[:1])
+	if attr, ok := body.Attributes[name]; ok && attrSch != nil && attrSch.NestedType != nil {
+		ranges = append(ranges, configExprUsageRanges(attr.Expr, attrSch.NestedType, path[1:], isAdd)...)
+	}
+	var nblk *BlockSchema
+	if nestedBlkSch != nil {
+		nblk = nestedBlkSch.Block
+	}
+	for _, nbody := range configNestedBodies(body, name) {
+		ranges = append(ranges, configUsageRanges(nbody, nblk, path[1:], isAdd)...)
+	}
+	return ranges
+}
+
+// configExprUsageRanges returns the ranges of the items of the path that are set in the objects of the nested attribute expression,
+// where "nt" is the schema of the nested attribute. If isAdd is true, it returns the ranges of the objects that don't set the path instead.
+// Only the object and tuple constructors are matched, as the values of the other expressions are unknown.
+func configExprUsageRanges(expr hclsyntax.Expression, nt *NestedTypeSchema, path []string, isAdd bool) []hcl.Range {
+	var objs []*hclsyntax.ObjectConsExpr
+	switch nt.NestingMode {
+	case schema.NestingList, schema.NestingSet:
+		if tuple, ok := expr.(*hclsyntax.TupleConsExpr); ok {
+			for _, expr := range tuple.Exprs {
+				if obj, ok := expr.(*hclsyntax.ObjectConsExpr); ok {
+					objs = append(objs, obj)
+				}
+			}
+		}
+	case schema.NestingMap:
+		// The keys of the map are arbitrary, the objects are the values
+		if m, ok := expr.(*hclsyntax.ObjectConsExpr); ok {
+			for _, item := range m.Items {
+				if obj, ok := item.ValueExpr.(*hclsyntax.ObjectConsExpr); ok {
+					objs = append(objs, obj)
+				}
+			}
+		}
+	default:
+		if obj, ok := expr.(*hclsyntax.ObjectConsExpr); ok {
+			objs = append(objs, obj)
+		}
+	}
+
+	var ranges []hcl.Range
+	for _, obj := range objs {
+		var item *hclsyntax.ObjectConsItem
+		for i := range obj.Items {
+			if hcl.ExprAsKeyword(obj.Items[i].KeyExpr) == path[0] {
+				item = &obj.Items[i]
+				break
+			}
+		}
+		switch {
+		case len(path) == 1 && isAdd:
+			if item == nil {
+				ranges = append(ranges, obj.SrcRange)
+			}
+		case len(path) == 1:
+			if item != nil {
+				ranges = append(ranges, hcl.RangeBetween(item.KeyExpr.Range(), item.ValueExpr.Range()))
+			}
+		case item != nil:
+			if attr := nt.Attributes[path[0]]; attr != nil && attr.NestedType != nil {
+				ranges = append(ranges, configExprUsageRanges(item.ValueExpr, attr.NestedType, path[1:], isAdd)...)
+			}
+		}
+	}
+	return ranges
+}
+
+// configNestedBodies returns the bodies of the nested blocks of the name, including the content of the dynamic blocks.
+func configNestedBodies(body *hclsyntax.Body, name string) []*hclsyntax.Body {
+	var bodies []*hclsyntax.Body
+	for _, blk := range body.Blocks {
+		switch {
+		case blk.Type == name:
+			bodies = append(bodies, blk.Body)
+		case blk.Type == "dynamic" && len(blk.Labels) == 1 && blk.Labels[0] == name:
+			for _, cblk := range blk.Body.Blocks {
+				if cblk.Type == "content" {
+					bodies = append(bodies, cblk.Body)
+				}
+			}
+		}
+	}
+	return bodies
+}
@@ -0,0 +1,214 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/magodo/tfpluginschema/schema"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestImpact(t *testing.T) {
+	osch := ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"name": {Type: cty.String, Required: true},
+						"old":  {Type: cty.String, Optional: true},
+					},
+					NestedBlocks: map[string]*NestedBlockSchema{
+						"rule": {
+							NestingMode: schema.NestingList,
+							Block: &BlockSchema{
+								Attributes: map[string]*AttributeSchema{
+									"ip": {Type: cty.String, Optional: true},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	nsch := ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"name":     {Type: cty.String, Required: true},
+						"location": {Type: cty.String, Required: true},
+					},
+					NestedBlocks: map[string]*NestedBlockSchema{
+						"rule": {
+							NestingMode: schema.NestingList,
+							Block:       &BlockSchema{},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	config := `resource "foo_resource" "a" {
+  name = "a"
+  old  = "x"
+  rule {
+    ip = "1.2.3.4"
+  }
+  dynamic "rule" {
+    for_each = []
+    content {
+      ip = rule.value
+    }
+  }
+}
+
+resource "foo_resource" "b" {
+  name     = "b"
+  location = "l"
+}
+
+resource "bar_resource" "c" {
+  old = "x"
+}
+
+output "old" {
+  value = "${foo_resource.b.old}-${foo_resource.b.name}"
+}
+
+locals {
+  ips = [for r in foo_resource.a[0].rule : r.ip]
+  ip  = foo_resource.a["k"].rule[0].ip
+  nested = [for r in foo_resource.a[0].rule : [for i in r.ip : i]]
+  keys   = [for k, r in foo_resource.a[0].rule : k]
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(config), "main.tf", hcl.InitialPos)
+	require.False(t, diags.HasErrors())
+
+	usages, err := impact(context.TODO(), osch, nsch, []*hcl.File{f}, ImpactOpt{Opt: Opt{Rules: []string{"R003", "R008"}}})
+	require.NoError(t, err)
+
+	var actual []string
+	for _, u := range usages {
+		actual = append(actual, u.String())
+	}
+	require.Equal(t, []string{
+		`main.tf:3,3-13: foo_resource.a: [R003] Attribute "old" of resource foo_resource is deleted`,
+		`main.tf:5,5-19: foo_resource.a: [R003] Attribute "rule.ip" of resource foo_resource is deleted`,
+		`main.tf:10,7-22: foo_resource.a: [R003] Attribute "rule.ip" of resource foo_resource is deleted`,
+		`main.tf:1,29-13,2: foo_resource.a: [R008] Attribute "location" of resource foo_resource is added`,
+		`main.tf:25,14-32: foo_resource.b: [R003] Attribute "old" of resource foo_resource is deleted (referenced as foo_resource.b.old)`,
+		`main.tf:29,44-48: foo_resource.a: [R003] Attribute "rule.ip" of resource foo_resource is deleted (referenced as foo_resource.a[0].rule[*].ip)`,
+		`main.tf:30,9-39: foo_resource.a: [R003] Attribute "rule.ip" of resource foo_resource is deleted (referenced as foo_resource.a["k"].rule[0].ip)`,
+		`main.tf:31,57-61: foo_resource.a: [R003] Attribute "rule.ip" of resource foo_resource is deleted (referenced as foo_resource.a[0].rule[*].ip)`,
+		`main.tf:31,64-65: foo_resource.a: [R003] Attribute "rule.ip" of resource foo_resource is deleted (referenced as foo_resource.a[0].rule[*].ip[*])`,
+	}, actual)
+}
+
+func TestImpactNestedAttributes(t *testing.T) {
+	nested := func(mode schema.NestingMode, attrs map[string]*AttributeSchema) *AttributeSchema {
+		return &AttributeSchema{Optional: true, NestedType: &NestedTypeSchema{NestingMode: mode, Attributes: attrs}}
+	}
+	osch := ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"na": nested(schema.NestingList, map[string]*AttributeSchema{
+							"x": {Type: cty.String, Optional: true},
+							"y": {Type: cty.String, Optional: true},
+						}),
+						"nm": nested(schema.NestingMap, map[string]*AttributeSchema{
+							"x": {Type: cty.String, Optional: true},
+						}),
+						"ns": nested(schema.NestingSingle, map[string]*AttributeSchema{
+							"inner": nested(schema.NestingList, map[string]*AttributeSchema{
+								"x": {Type: cty.String, Optional: true},
+							}),
+						}),
+					},
+				},
+			},
+		},
+	}
+	nsch := ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"na": nested(schema.NestingList, map[string]*AttributeSchema{
+							"y": {Type: cty.String, Optional: true},
+							"z": {Type: cty.String, Required: true},
+						}),
+						"nm": nested(schema.NestingMap, map[string]*AttributeSchema{}),
+						"ns": nested(schema.NestingSingle, map[string]*AttributeSchema{
+							"inner": nested(schema.NestingList, map[string]*AttributeSchema{}),
+						}),
+					},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name   string
+		config string
+		expect []string
+	}{
+		{
+			name: "Object and tuple constructors",
+			config: `resource "foo_resource" "a" {
+  na = [{ x = "1", y = "2" }, { y = "3", z = "4" }]
+  nm = {
+    x = { x = "5" }
+  }
+  ns = {
+    inner = [{ x = "6" }]
+  }
+}
+`,
+			expect: []string{
+				`main.tf:2,11-18: foo_resource.a: [R003] Attribute "na.x" of resource foo_resource is deleted`,
+				`main.tf:4,11-18: foo_resource.a: [R003] Attribute "nm.x" of resource foo_resource is deleted`,
+				`main.tf:7,16-23: foo_resource.a: [R003] Attribute "ns.inner.x" of resource foo_resource is deleted`,
+				`main.tf:2,9-29: foo_resource.a: [R008] Attribute "na.z" of resource foo_resource is added`,
+			},
+		},
+		{
+			name: "Traversals with index steps",
+			config: `locals {
+  x  = foo_resource.a.na[0].x
+  xs = foo_resource.a.na[*].x
+  xi = foo_resource.a.na[count.index].x
+  xm = foo_resource.a.nm["k"].x
+}
+`,
+			expect: []string{
+				`main.tf:2,8-30: foo_resource.a: [R003] Attribute "na.x" of resource foo_resource is deleted (referenced as foo_resource.a.na[0].x)`,
+				`main.tf:3,8-30: foo_resource.a: [R003] Attribute "na.x" of resource foo_resource is deleted (referenced as foo_resource.a.na[*].x)`,
+				`main.tf:4,8-40: foo_resource.a: [R003] Attribute "na.x" of resource foo_resource is deleted (referenced as foo_resource.a.na[*].x)`,
+				`main.tf:5,8-32: foo_resource.a: [R003] Attribute "nm.x" of resource foo_resource is deleted (referenced as foo_resource.a.nm["k"].x)`,
+			},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			f, diags := hclsyntax.ParseConfig([]byte(tt.config), "main.tf", hcl.InitialPos)
+			require.False(t, diags.HasErrors())
+
+			usages, err := impact(context.TODO(), osch, nsch, []*hcl.File{f}, ImpactOpt{Opt: Opt{Rules: []string{"R003", "R008"}}})
+			require.NoError(t, err)
+
+			var actual []string
+			for _, u := range usages {
+				actual = append(actual, u.String())
+			}
+			require.Equal(t, tt.expect, actual)
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/open-policy-agent/opa/rego"
+)
+
+// NormalizedSchema is the normalized form of a provider schema, where the resources, attributes and blocks are flattened,
+// with the same shapes as those in the changes.
+type NormalizedSchema struct {
+	Resources  []SchemaResource  `json:"resources"`
+	Attributes []SchemaAttribute `json:"attributes"`
+	Blocks     []SchemaBlock     `json:"blocks"`
+}
+
+// SchemaObject is a resource, an attribute or a block of the normalized schema.
+type SchemaObject interface {
+	isSchemaObject()
+	String() string
+}
+
+type SchemaResource struct {
+	Type         string `json:"type"`
+	IsDataSource bool   `json:"is_data_source"`
+	IsEphemeral  bool   `json:"is_ephemeral"`
+	Resource
+}
+
+func (SchemaResource) isSchemaObject() {}
+
+func (o SchemaResource) String() string {
+	if o.IsDataSource {
+		return "Data Source " + o.Type
+	}
+	if o.IsEphemeral {
+		return "Ephemeral Resource " + o.Type
+	}
+	return "Resource " + o.Type
+}
+
+func (o SchemaResource) MarshalJSON() ([]byte, error) {
+	type alias SchemaResource
+	return injectMarshal(alias(o), func(m map[string]interface{}) {
+		m["kind"] = ChangeKindResource
+	})
+}
+
+type SchemaAttribute struct {
+	Scope Scope    `json:"scope"`
+	Path  []string `json:"path"`
+	Attribute
+}
+
+func (SchemaAttribute) isSchemaObject() {}
+
+func (o SchemaAttribute) String() string {
+	return fmt.Sprintf("Attribute %q of %s", strings.Join(o.Path, "."), scopeString(o.Scope))
+}
+
+func (o SchemaAttribute) MarshalJSON() ([]byte, error) {
+	type alias SchemaAttribute
+	return injectMarshal(alias(o), func(m map[string]interface{}) {
+		m["kind"] = ChangeKindAttribute
+	})
+}
+
+type SchemaBlock struct {
+	Scope Scope    `json:"scope"`
+	Path  []string `json:"path"`
+	Block
+}
+
+func (SchemaBlock) isSchemaObject() {}
+
+func (o SchemaBlock) String() string {
+	return fmt.Sprintf("Block %q of %s", strings.Join(o.Path, "."), scopeString(o.Scope))
+}
+
+func (o SchemaBlock) MarshalJSON() ([]byte, error) {
+	type alias SchemaBlock
+	return injectMarshal(alias(o), func(m map[string]interface{}) {
+		m["kind"] = ChangeKindBlock
+	})
+}
+
+// Objects returns all the resources, attributes and blocks of the normalized schema.
+func (s NormalizedSchema) Objects() []SchemaObject {
+	var objects []SchemaObject
+	for _, o := range s.Resources {
+		objects = append(objects, o)
+	}
+	for _, o := range s.Attributes {
+		objects = append(objects, o)
+	}
+	for _, o := range s.Blocks {
+		objects = append(objects, o)
+	}
+	return objects
+}
+
+func NormalizeSchema(sch *ProviderSchema) NormalizedSchema {
+	var out NormalizedSchema
+	if sch.Provider != nil && sch.Provider.Block != nil {
+		out.normalizeBlock(ProviderScope{}, []string{}, sch.Provider.Block)
+	}
+	if blk := sch.providerMetaBlock(); blk != nil {
+		out.normalizeBlock(ProviderMetaScope{}, []string{}, blk)
+	}
+	for _, item := range []struct {
+		m            map[string]*ResourceSchema
+		isDataSource bool
+		isEphemeral  bool
+	}{
+		{sch.DataSourceSchemas, true, false},
+		{sch.ResourceSchemas, false, false},
+		{sch.EphemeralResourceSchemas, false, true},
+	} {
+		for _, rt := range mapSortedKeys(item.m) {
+			res := item.m[rt]
+			out.Resources = append(out.Resources, SchemaResource{
+				Type:         rt,
+				IsDataSource: item.isDataSource,
+				IsEphemeral:  item.isEphemeral,
+				Resource:     *NewResource(res),
+			})
+			if res.Block != nil {
+				out.normalizeBlock(ResourceScope{Type: rt, IsDataSource: item.isDataSource, IsEphemeral: item.isEphemeral}, []string{}, res.Block)
+			}
+		}
+	}
+	identities := sch.resourceIdentityBlocks()
+	for _, rt := range mapSortedKeys(identities) {
+		out.normalizeBlock(IdentityScope{Type: rt}, []string{}, identities[rt])
+	}
+	return out
+}
+
+func (s *NormalizedSchema) normalizeBlock(scope Scope, path []string, blk *BlockSchema) {
+	s.normalizeAttributes(scope, path, blk.Attributes)
+	for _, name := range mapSortedKeys(blk.NestedBlocks) {
+		nestedBlk := blk.NestedBlocks[name]
+		npath := append(append([]string{}, path...), name)
+		s.Blocks = append(s.Blocks, SchemaBlock{
+			Scope: scope,
+			Path:  npath,
+			Block: *NewNestedBlock(nestedBlk),
+		})
+		if nestedBlk.Block != nil {
+			s.normalizeBlock(scope, npath, nestedBlk.Block)
+		}
+	}
+}
+
+func (s *NormalizedSchema) normalizeAttributes(scope Scope, path []string, attrs map[string]*AttributeSchema) {
+	for _, name := range mapSortedKeys(attrs) {
+		attr := attrs[name]
+		npath := append(append([]string{}, path...), name)
+		s.Attributes = append(s.Attributes, SchemaAttribute{
+			Scope:     scope,
+			Path:      npath,
+			Attribute: *NewAttribute(attr),
+		})
+		if nt := attr.NestedType; nt != nil {
+			s.normalizeAttributes(scope, npath, nt.Attributes)
+		}
+	}
+}
+
+// normalizedSchemaInput returns the normalized schema as a Go map (default), which will then be able to be processed by rego.
+func normalizedSchemaInput(sch *ProviderSchema) (interface{}, error) {
+	return regoInput(NormalizeSchema(sch))
+}
+
+// regoInput marshals and unmarshals back the value to a Go map (default), which will then be able to be processed by rego.
+func regoInput(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var input interface{}
+	if err := json.Unmarshal(b, &input); err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+type InspectOpt struct {
+	// Query is the Rego query evaluated against the normalized schema (i.e. NormalizedSchema) as the input.
+	Query string
+	// ModuleFiles are the paths to the Rego modules that can be referenced by the query.
+	ModuleFiles []string
+}
+
+// InspectResult is a result of the query, including the values of each expression and the bindings of the variables.
+type InspectResult struct {
+	Expressions []interface{}          `json:"expressions"`
+	Bindings    map[string]interface{} `json:"bindings,omitempty"`
+}
+
+// Inspect evaluates a Rego query against a single normalized schema, and returns the results in JSON.
+func Inspect(ctx context.Context, path string, opt InspectOpt) (string, error) {
+	sch, err := loadSchema(path)
+	if err != nil {
+		return "", err
+	}
+	var modules []func(*rego.Rego)
+	for _, f := range opt.ModuleFiles {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return "", fmt.Errorf("reading the module file %s: %v", f, err)
+		}
+		modules = append(modules, rego.Module(f, string(b)))
+	}
+	results, err := inspect(ctx, sch, opt.Query, modules...)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func inspect(ctx context.Context, sch *ProviderSchema, query string, options ...func(*rego.Rego)) ([]InspectResult, error) {
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	input, err := normalizedSchemaInput(sch)
+	if err != nil {
+		return nil, err
+	}
+
+	options = append(options, rego.Query(query), rego.Imports([]string{"future.keywords.in"}))
+	r := rego.New(options...)
+	pq, err := r.PrepareForEval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := pq.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return nil, err
+	}
+
+	results := []InspectResult{}
+	for _, r := range rs {
+		var res InspectResult
+		for _, expr := range r.Expressions {
+			res.Expressions = append(res.Expressions, expr.Value)
+		}
+		if len(r.Bindings) != 0 {
+			res.Bindings = r.Bindings
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
@@ -0,0 +1,112 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/magodo/tfpluginschema/schema"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestNormalizeSchema(t *testing.T) {
+	sch := &ProviderSchema{
+		Provider: &ConfigSchema{
+			Block: &BlockSchema{
+				Attributes: map[string]*AttributeSchema{
+					"endpoint": {Type: cty.String, Optional: true},
+				},
+			},
+		},
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				SchemaVersion: 1,
+				Block: &BlockSchema{
+					NestedBlocks: map[string]*NestedBlockSchema{
+						"blk": {
+							NestingMode: schema.NestingList,
+							MaxItems:    1,
+							Block: &BlockSchema{
+								Attributes: map[string]*AttributeSchema{
+									"attr": {Type: cty.Bool, Required: true},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	expect := NormalizedSchema{
+		Resources: []SchemaResource{
+			{
+				Type:     "foo_resource",
+				Resource: Resource{SchemaVersion: 1},
+			},
+		},
+		Attributes: []SchemaAttribute{
+			{
+				Scope:     ProviderScope{},
+				Path:      []string{"endpoint"},
+				Attribute: Attribute{Type: cty.String, Optional: true},
+			},
+			{
+				Scope:     ResourceScope{Type: "foo_resource"},
+				Path:      []string{"blk", "attr"},
+				Attribute: Attribute{Type: cty.Bool, Required: true},
+			},
+		},
+		Blocks: []SchemaBlock{
+			{
+				Scope: ResourceScope{Type: "foo_resource"},
+				Path:  []string{"blk"},
+				Block: Block{NestingMode: schema.NestingList, MaxItems: 1},
+			},
+		},
+	}
+	require.Equal(t, expect, NormalizeSchema(sch))
+}
+
+func TestInspect(t *testing.T) {
+	sch := &ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					NestedBlocks: map[string]*NestedBlockSchema{
+						"single": {
+							NestingMode: schema.NestingList,
+							MaxItems:    1,
+							Block:       &BlockSchema{},
+						},
+						"multi": {
+							NestingMode: schema.NestingList,
+							Block:       &BlockSchema{},
+						},
+					},
+				},
+			},
+			"bar_resource": {
+				Block: &BlockSchema{
+					NestedBlocks: map[string]*NestedBlockSchema{
+						"single": {
+							NestingMode: schema.NestingSet,
+							MaxItems:    1,
+							Block:       &BlockSchema{},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	results, err := inspect(context.TODO(), sch, `x := {b.scope.type | some b in input.blocks; b.max_items == 1}`)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	b, err := json.Marshal(results[0].Bindings["x"])
+	require.NoError(t, err)
+	require.JSONEq(t, `["bar_resource", "foo_resource"]`, string(b))
+
+	_, err = inspect(context.TODO(), sch, `x := `)
+	require.Error(t, err)
+}
@@ -0,0 +1,176 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/open-policy-agent/opa/rego"
+)
+
+// RuleCategoryLint is for rules that check the provider design conventions on a single schema.
+const RuleCategoryLint RuleCategory = "lint"
+
+// LintRules are the pre-defined lint rules, whose expressions are evaluated against each object (referenced as "o") of the normalized schema.
+var LintRules = map[string]Rule{
+	"L001": {
+		ID:          "L001",
+		Category:    RuleCategoryLint,
+		Description: "A ForceNew block has arguments that are not ForceNew",
+		Expr:        `o.kind == "block"; o.force_new; some a in input.attributes; a.scope == o.scope; count(a.path) == count(o.path) + 1; array.slice(a.path, 0, count(o.path)) == o.path; true in {a.required, a.optional}; not a.force_new`,
+	},
+	"L002": {
+		ID:          "L002",
+		Category:    RuleCategoryLint,
+		Description: "A required attribute has a default value",
+		Expr:        `o.kind == "attribute"; o.required; o["default"] != null`,
+	},
+	"L003": {
+		ID:          "L003",
+		Category:    RuleCategoryLint,
+		Description: "The id attribute is declared",
+		Expr:        `o.kind == "attribute"; o.scope.kind == "resource"; o.path == ["id"]`,
+	},
+	"L004": {
+		ID:          "L004",
+		Category:    RuleCategoryLint,
+		Description: "A constraint (e.g. conflicts_with) references a non-existent path",
+		Expr:        `o.kind in {"attribute", "block"}; some ref in input.broken_references; ref.scope == o.scope; ref.path == o.path`,
+	},
+}
+
+func buildLintRegoModule(expr string) string {
+	return fmt.Sprintf(`package provider
+
+import future.keywords.in
+
+lint_violation[i] {
+	some i, o in input.objects
+	%s
+}
+`, expr)
+}
+
+// lintReference is a path referenced by the constraint of an attribute or a block, which can't be resolved, see BrokenReference.
+type lintReference struct {
+	Scope      Scope    `json:"scope"`
+	Path       []string `json:"path"`
+	Constraint string   `json:"constraint"`
+	Reference  string   `json:"reference"`
+}
+
+type LintOpt struct {
+	// Rules are the IDs of the pre-defined lint rules. All the pre-defined lint rules are used if neither Rules nor CustomRuleExprs is specified.
+	Rules           []string
+	CustomRuleExprs []string
+	// ExcludeRules are the IDs of the pre-defined lint rules to exclude from the rules selected above.
+	ExcludeRules []string
+}
+
+type LintResult struct {
+	Rule     string
+	Category RuleCategory
+	Object   SchemaObject
+}
+
+func Lint(ctx context.Context, path string, opt LintOpt) (string, error) {
+	sch, err := loadSchema(path)
+	if err != nil {
+		return "", err
+	}
+	results, err := lint(ctx, sch, opt)
+	if err != nil {
+		return "", err
+	}
+	var out []string
+	for _, res := range results {
+		out = append(out, fmt.Sprintf("[%s] %s: %s", res.Rule, res.Object, lintRuleDescription(res.Rule)))
+	}
+	return strings.Join(out, "\n"), nil
+}
+
+func lintRuleDescription(id string) string {
+	if rule, ok := LintRules[id]; ok {
+		return rule.Description
+	}
+	return "Custom lint rule is violated"
+}
+
+func lint(ctx context.Context, sch *ProviderSchema, opt LintOpt) ([]LintResult, error) {
+	var rules []Rule
+	for _, name := range opt.Rules {
+		rule, ok := LintRules[name]
+		if !ok {
+			return nil, fmt.Errorf("undefined lint rule: %s", name)
+		}
+		rules = append(rules, rule)
+	}
+	for idx, expr := range opt.CustomRuleExprs {
+		rules = append(rules, Rule{
+			ID:   fmt.Sprintf("CUSTOM-%d", idx),
+			Expr: expr,
+		})
+	}
+	if len(rules) == 0 {
+		for _, name := range mapSortedKeys(LintRules) {
+			rules = append(rules, LintRules[name])
+		}
+	}
+	rules, err := excludeRules(rules, LintRules, opt.ExcludeRules)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := []lintReference{}
+	for _, ref := range CheckReferences(&ProviderSchema{}, sch) {
+		refs = append(refs, lintReference{
+			Scope:      ref.Scope,
+			Path:       ref.Path,
+			Constraint: ref.Constraint,
+			Reference:  ref.Reference,
+		})
+	}
+
+	nsch := NormalizeSchema(sch)
+	objects := nsch.Objects()
+	type LintInput struct {
+		NormalizedSchema
+		Objects          []SchemaObject  `json:"objects"`
+		BrokenReferences []lintReference `json:"broken_references"`
+	}
+	input, err := regoInput(LintInput{
+		NormalizedSchema: nsch,
+		Objects:          objects,
+		BrokenReferences: refs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []LintResult
+	for _, rule := range rules {
+		r := rego.New(
+			rego.Query("data.provider.lint_violation"),
+			rego.Module("lint", buildLintRegoModule(rule.Expr)))
+
+		query, err := r.PrepareForEval(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("preparing lint rule %s: %v", rule.ID, err)
+		}
+		rs, err := query.Eval(ctx, rego.EvalInput(input))
+		if err != nil {
+			return nil, fmt.Errorf("evaluating lint rule %s: %v", rule.ID, err)
+		}
+
+		for _, idx := range rs[0].Expressions[0].Value.([]interface{}) {
+			idx, _ := idx.(json.Number).Int64()
+			results = append(results, LintResult{
+				Rule:     rule.ID,
+				Category: rule.Category,
+				Object:   objects[idx],
+			})
+		}
+	}
+	return results, nil
+}
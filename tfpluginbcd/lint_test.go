@@ -0,0 +1,132 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/magodo/tfpluginschema/schema"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestLint(t *testing.T) {
+	sch := &ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"id": {
+							Type:     cty.String,
+							Computed: true,
+						},
+						"name": {
+							Type:          cty.String,
+							Required:      true,
+							Default:       "foo",
+							ConflictsWith: []string{"blk.0.attr", "not_exist"},
+						},
+					},
+					NestedBlocks: map[string]*NestedBlockSchema{
+						"blk": {
+							NestingMode: schema.NestingList,
+							Optional:    true,
+							ForceNew:    true,
+							Block: &BlockSchema{
+								Attributes: map[string]*AttributeSchema{
+									"attr": {
+										Type:         cty.String,
+										Optional:     true,
+										RequiredWith: []string{"blk.0.force_new_attr"},
+									},
+									"other_attr": {
+										Type:         cty.String,
+										Optional:     true,
+										ExactlyOneOf: []string{"blk.0.other_attr", "blk.0.gone"},
+									},
+									"computed_attr": {
+										Type:     cty.String,
+										Computed: true,
+									},
+									"force_new_attr": {
+										Type:     cty.String,
+										Optional: true,
+										ForceNew: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		opt      LintOpt
+		expect   []string
+		hasError bool
+	}{
+		{
+			name: "all rules",
+			expect: []string{
+				`[L001] Block "blk" of resource foo_resource`,
+				`[L002] Attribute "name" of resource foo_resource`,
+				`[L003] Attribute "id" of resource foo_resource`,
+				`[L004] Attribute "name" of resource foo_resource`,
+				`[L004] Attribute "blk.other_attr" of resource foo_resource`,
+			},
+		},
+		{
+			name: "exclude rules",
+			opt: LintOpt{
+				ExcludeRules: []string{"L001", "L003"},
+			},
+			expect: []string{
+				`[L002] Attribute "name" of resource foo_resource`,
+				`[L004] Attribute "name" of resource foo_resource`,
+				`[L004] Attribute "blk.other_attr" of resource foo_resource`,
+			},
+		},
+		{
+			name: "undefined rule to exclude",
+			opt: LintOpt{
+				ExcludeRules: []string{"Lxxx"},
+			},
+			hasError: true,
+		},
+		{
+			name: "custom rule",
+			opt: LintOpt{
+				CustomRuleExprs: []string{`o.kind == "attribute"; o.computed`},
+			},
+			expect: []string{
+				`[CUSTOM-0] Attribute "id" of resource foo_resource`,
+				`[CUSTOM-0] Attribute "blk.computed_attr" of resource foo_resource`,
+			},
+		},
+		{
+			name: "undefined rule",
+			opt: LintOpt{
+				Rules: []string{"Lxxx"},
+			},
+			hasError: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := lint(context.TODO(), sch, tt.opt)
+			if tt.hasError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			var actual []string
+			for _, res := range results {
+				actual = append(actual, "["+res.Rule+"] "+res.Object.String())
+			}
+			require.Equal(t, tt.expect, actual)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+type MatrixOpt struct {
+	Opt
+
+	// Full compares each pair of the schema files (in order), instead of comparing each old schema file to the last (new) one.
+	Full bool
+}
+
+// MatrixResult is the result of the comparison between two schema versions.
+type MatrixResult struct {
+	OldVersion string
+	NewVersion string
+	Output     []string
+}
+
+// RunMatrix runs the breaking change detector for multiple pairs of schemas. By default, each of the leading (old) schema files is
+// compared to the last (new) schema file. In full mode, each pair of the schema files are compared (in order).
+// The paths can also be a single directory, where the schema files (*.json) are ordered by their versions (i.e. the file name without extension).
+func RunMatrix(ctx context.Context, paths []string, opt MatrixOpt) (string, error) {
+	files, err := listSchemaFiles(paths)
+	if err != nil {
+		return "", err
+	}
+	// Each schema is only loaded once
+	var schemas []*ProviderSchema
+	for _, f := range files {
+		sch, err := loadSchema(f.Path)
+		if err != nil {
+			return "", err
+		}
+		schemas = append(schemas, sch)
+	}
+
+	results, err := runMatrix(ctx, files, schemas, opt)
+	if err != nil {
+		return "", err
+	}
+
+	var out []string
+	for _, res := range results {
+		out = append(out, fmt.Sprintf("=== %s -> %s ===", res.OldVersion, res.NewVersion))
+		out = append(out, res.Output...)
+		out = append(out, "")
+	}
+	return strings.TrimSuffix(strings.Join(out, "\n"), "\n"), nil
+}
+
+func runMatrix(ctx context.Context, files []schemaFile, schemas []*ProviderSchema, opt MatrixOpt) ([]MatrixResult, error) {
+	type pair struct{ o, n int }
+	var pairs []pair
+	last := len(schemas) - 1
+	if opt.Full {
+		for i := 0; i < last; i++ {
+			for j := i + 1; j <= last; j++ {
+				pairs = append(pairs, pair{i, j})
+			}
+		}
+	} else {
+		for i := 0; i < last; i++ {
+			pairs = append(pairs, pair{i, last})
+		}
+	}
+
+	// The rules are only prepared once for all the pairs
+	rules, err := buildRules(opt.Opt)
+	if err != nil {
+		return nil, err
+	}
+	prules, err := PrepareRules(ctx, rules)
+	if err != nil {
+		return nil, fmt.Errorf("preparing rules: %v", err)
+	}
+
+	var results []MatrixResult
+	for _, p := range pairs {
+		output, err := runPrepared(ctx, *schemas[p.o], *schemas[p.n], prules, opt.Opt)
+		if err != nil {
+			return nil, fmt.Errorf("%s -> %s: %v", files[p.o].Version, files[p.n].Version, err)
+		}
+		results = append(results, MatrixResult{
+			OldVersion: files[p.o].Version,
+			NewVersion: files[p.n].Version,
+			Output:     output,
+		})
+	}
+	return results, nil
+}
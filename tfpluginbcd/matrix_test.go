@@ -0,0 +1,92 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunMatrix(t *testing.T) {
+	resourceSchema := func(types ...string) *ProviderSchema {
+		m := map[string]*ResourceSchema{}
+		for _, rt := range types {
+			m[rt] = &ResourceSchema{
+				Block: &BlockSchema{
+					Attributes:   map[string]*AttributeSchema{},
+					NestedBlocks: map[string]*NestedBlockSchema{},
+				},
+			}
+		}
+		return &ProviderSchema{ResourceSchemas: m}
+	}
+	files := []schemaFile{{Version: "v1"}, {Version: "v2"}, {Version: "v3"}}
+	schemas := []*ProviderSchema{
+		resourceSchema("a", "b", "c"),
+		resourceSchema("b", "c"),
+		resourceSchema("c"),
+	}
+
+	cases := []struct {
+		name   string
+		full   bool
+		expect []MatrixResult
+	}{
+		{
+			name: "to the new version",
+			expect: []MatrixResult{
+				{
+					OldVersion: "v1",
+					NewVersion: "v3",
+					Output: []string{
+						"[R001] Resource a is deleted",
+						"[R001] Resource b is deleted",
+					},
+				},
+				{
+					OldVersion: "v2",
+					NewVersion: "v3",
+					Output: []string{
+						"[R001] Resource b is deleted",
+					},
+				},
+			},
+		},
+		{
+			name: "full",
+			full: true,
+			expect: []MatrixResult{
+				{
+					OldVersion: "v1",
+					NewVersion: "v2",
+					Output: []string{
+						"[R001] Resource a is deleted",
+					},
+				},
+				{
+					OldVersion: "v1",
+					NewVersion: "v3",
+					Output: []string{
+						"[R001] Resource a is deleted",
+						"[R001] Resource b is deleted",
+					},
+				},
+				{
+					OldVersion: "v2",
+					NewVersion: "v3",
+					Output: []string{
+						"[R001] Resource b is deleted",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := runMatrix(context.TODO(), files, schemas, MatrixOpt{Opt: Opt{Rules: []string{"R001"}}, Full: tt.full})
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, actual)
+		})
+	}
+}
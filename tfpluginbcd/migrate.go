@@ -0,0 +1,456 @@
+package tfpluginbcd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/magodo/tfpluginschema/schema"
+	"github.com/pmezard/go-difflib/difflib"
+	"golang.org/x/exp/slices"
+)
+
+type MigrateOpt struct {
+	// ProviderName is the local name of the provider, see ImpactOpt.
+	ProviderName string
+
+	// DryRun doesn't write the files, but shows the diffs instead.
+	DryRun bool
+}
+
+// migration is the set of mechanical fixes derived from the changes between the old and new schemas.
+type migration struct {
+	// renames are the renamed attributes and blocks, see DetectRenames.
+	renames []Rename
+	// removes are the deleted optional attributes that are not renamed.
+	removes []AttributeChange
+	// listifies are the blocks whose nesting mode is changed from single to list.
+	listifies []BlockChange
+}
+
+func newMigration(osch, nsch *ProviderSchema) migration {
+	changes := Compare(osch, nsch)
+	m := migration{
+		renames: DetectRenames(osch, nsch, changes),
+	}
+	for _, change := range changes {
+		switch change := change.(type) {
+		case AttributeChange:
+			if !change.IsDelete || change.Previous == nil || !change.Previous.Optional {
+				continue
+			}
+			if slices.IndexFunc(m.renames, func(r Rename) bool {
+				return !r.IsBlock && scopeAddress(r.Scope) == scopeAddress(change.Scope) && slices.Equal(r.From, change.Path)
+			}) != -1 {
+				continue
+			}
+			m.removes = append(m.removes, change)
+		case BlockChange:
+			if !change.IsModify || change.Modification.NestingMode == nil {
+				continue
+			}
+			if change.Modification.NestingMode.From == schema.NestingSingle && change.Modification.NestingMode.To == schema.NestingList {
+				m.listifies = append(m.listifies, change)
+			}
+		}
+	}
+	return m
+}
+
+// Migrate applies the mechanical fixes to the Terraform configuration files (*.tf) under the directory (recursively), based on the changes
+// between the old and new schemas:
+//
+// - Rename the renamed arguments and blocks (see DetectRenames), including the references in the expressions
+// - Remove the deleted optional arguments, with a comment left in place of each of them
+// - Add the "[0]" index to the references into the blocks whose nesting mode is changed from single to list
+//
+// It returns the paths of the modified files, or the diffs of them in dry run mode.
+func Migrate(opath, npath, dir string, opt MigrateOpt) (string, error) {
+	osch, nsch, err := loadSchemas(opath, npath)
+	if err != nil {
+		return "", err
+	}
+	paths, err := listConfigFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	providerName := opt.ProviderName
+	if providerName == "" {
+		providerName = providerLocalName(nsch)
+	}
+	m := newMigration(osch, nsch)
+
+	// All the files are migrated in memory before any of them is written, so that an error doesn't leave the files partially migrated.
+	type migratedFile struct {
+		path  string
+		mode  os.FileMode
+		b, nb []byte
+	}
+	var files []migratedFile
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("reading the configuration file %s: %v", path, err)
+		}
+		nb, err := migrateConfig(b, path, m, providerName)
+		if err != nil {
+			return "", err
+		}
+		if bytes.Equal(b, nb) {
+			continue
+		}
+		files = append(files, migratedFile{path: path, mode: info.Mode(), b: b, nb: nb})
+	}
+
+	var out []string
+	for _, f := range files {
+		if opt.DryRun {
+			diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+				A:        difflib.SplitLines(string(f.b)),
+				B:        difflib.SplitLines(string(f.nb)),
+				FromFile: f.path,
+				ToFile:   f.path,
+				Context:  3,
+			})
+			if err != nil {
+				return "", err
+			}
+			out = append(out, strings.TrimSuffix(diff, "\n"))
+			continue
+		}
+		if err := os.WriteFile(f.path, f.nb, f.mode); err != nil {
+			return "", fmt.Errorf("writing the configuration file %s: %v", f.path, err)
+		}
+		out = append(out, f.path)
+	}
+	return strings.Join(out, "\n"), nil
+}
+
+// migrateConfig applies the migration to the content of a configuration file, with the formatting of the unchanged parts preserved.
+func migrateConfig(b []byte, filename string, m migration, providerName string) ([]byte, error) {
+	f, diags := hclwrite.ParseConfig(b, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("parsing the configuration file %s: %v", filename, diags.Error())
+	}
+
+	var modified bool
+	for _, blk := range f.Body().Blocks() {
+		scope, _, ok := configBlockScope(blk.Type(), blk.Labels(), providerName)
+		if !ok {
+			continue
+		}
+		for _, change := range m.removes {
+			if change.Scope != scope {
+				continue
+			}
+			name := change.Path[len(change.Path)-1]
+			for _, body := range writeNestedBodies(blk.Body(), change.Path[:len(change.Path)-1]) {
+				attr := body.GetAttribute(name)
+				if attr == nil {
+					continue
+				}
+				value := strings.Join(strings.Fields(string(attr.Expr().BuildTokens(nil).Bytes())), " ")
+				commentOutWriteAttribute(attr, name, fmt.Sprintf("# %s = %s (removed, as it is deleted from the provider schema)", name, value))
+				modified = true
+			}
+		}
+		for _, r := range m.renames {
+			if r.Scope != scope {
+				continue
+			}
+			from, to := r.From[len(r.From)-1], r.To[len(r.To)-1]
+			for _, body := range writeNestedBodies(blk.Body(), r.From[:len(r.From)-1]) {
+				if !r.IsBlock {
+					if attr := body.GetAttribute(from); attr != nil {
+						renameWriteAttribute(attr, from, to)
+						modified = true
+					}
+					continue
+				}
+				for _, nblk := range body.Blocks() {
+					switch {
+					case nblk.Type() == from:
+						renameWriteBlock(nblk, from, to)
+						modified = true
+					case nblk.Type() == "dynamic" && slices.Equal(nblk.Labels(), []string{from}):
+						renameWriteBlock(nblk, from, to)
+						modified = true
+						// Keep the iterator name, which defaults to the block type, for the references in the content.
+						if nblk.Body().GetAttribute("iterator") == nil {
+							nblk.Body().SetAttributeTraversal("iterator", hcl.Traversal{hcl.TraverseRoot{Name: from}})
+							indentWriteAttribute(nblk.Body().GetAttribute("iterator"), writeBodyIndent(nblk))
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Rewrite the references in the expressions.
+	var walk func(body *hclwrite.Body)
+	walk = func(body *hclwrite.Body) {
+		for name, attr := range body.Attributes() {
+			if tokens, ok := migrateExprTokens(attr.Expr().BuildTokens(nil), m); ok {
+				body.SetAttributeRaw(name, tokens)
+				modified = true
+			}
+		}
+		for _, blk := range body.Blocks() {
+			walk(blk.Body())
+		}
+	}
+	walk(f.Body())
+
+	if !modified {
+		return b, nil
+	}
+	// The tokens are written as is, instead of via f.Bytes(), which formats the whole file. The generated tokens are laid out
+	// in place by themselves, so that the formatting of the rest of the file is preserved.
+	return f.BuildTokens(nil).Bytes(), nil
+}
+
+// writeNestedBodies returns the bodies of the nested blocks of the path, including the content of the dynamic blocks.
+func writeNestedBodies(body *hclwrite.Body, path []string) []*hclwrite.Body {
+	if len(path) == 0 {
+		return []*hclwrite.Body{body}
+	}
+	var bodies []*hclwrite.Body
+	for _, blk := range body.Blocks() {
+		switch {
+		case blk.Type() == path[0]:
+			bodies = append(bodies, writeNestedBodies(blk.Body(), path[1:])...)
+		case blk.Type() == "dynamic" && slices.Equal(blk.Labels(), path[:1]):
+			for _, cblk := range blk.Body().Blocks() {
+				if cblk.Type() == "content" {
+					bodies = append(bodies, writeNestedBodies(cblk.Body(), path[1:])...)
+				}
+			}
+		}
+	}
+	return bodies
+}
+
+// renameWriteAttribute renames the attribute in place, by updating its name token.
+func renameWriteAttribute(attr *hclwrite.Attribute, from, to string) {
+	for _, tok := range attr.BuildTokens(nil) {
+		if tok.Type == hclsyntax.TokenIdent && string(tok.Bytes) == from {
+			tok.Bytes = []byte(to)
+			return
+		}
+	}
+}
+
+// renameWriteBlock renames the block type (e.g. "rule") or the label of the dynamic block (e.g. `dynamic "rule"`) in place, by updating
+// its token. Unlike SetType and SetLabels, it keeps the spaces before the token.
+func renameWriteBlock(blk *hclwrite.Block, from, to string) {
+	for _, tok := range blk.BuildTokens(nil) {
+		if tok.Type == hclsyntax.TokenOBrace {
+			return
+		}
+		if (tok.Type == hclsyntax.TokenIdent || tok.Type == hclsyntax.TokenQuotedLit) && string(tok.Bytes) == from {
+			tok.Bytes = []byte(to)
+			return
+		}
+	}
+}
+
+// writeBodyIndent returns the indentation of the items in the block body, which is the one of the first item, or two spaces more than
+// the block itself if the body is empty.
+func writeBodyIndent(blk *hclwrite.Block) int {
+	tokens := blk.BuildTokens(nil)
+	indent := tokens[0].SpacesBefore + 2
+	for i, tok := range tokens {
+		if tok.Type != hclsyntax.TokenOBrace {
+			continue
+		}
+		for j := i + 1; j+1 < len(tokens); j++ {
+			if tokens[j].Type == hclsyntax.TokenNewline {
+				if next := tokens[j+1]; next.Type != hclsyntax.TokenNewline && next.Type != hclsyntax.TokenCBrace {
+					indent = next.SpacesBefore
+				}
+				break
+			}
+		}
+		break
+	}
+	return indent
+}
+
+// indentWriteAttribute lays out the tokens of the generated attribute "<name> = <expr>", which is indented by the spaces.
+func indentWriteAttribute(attr *hclwrite.Attribute, indent int) {
+	tokens := attr.BuildTokens(nil)
+	for i, tok := range tokens {
+		switch {
+		case i == 0:
+			tok.SpacesBefore = indent
+		case tok.Type == hclsyntax.TokenEqual || tokens[i-1].Type == hclsyntax.TokenEqual:
+			tok.SpacesBefore = 1
+		}
+	}
+}
+
+// commentOutWriteAttribute replaces the attribute in place with the line comment, by turning its name token into the comment
+// (followed by the original line comment, if any) and dropping the following tokens.
+func commentOutWriteAttribute(attr *hclwrite.Attribute, name, comment string) {
+	tokens := attr.BuildTokens(nil)
+	for i, tok := range tokens {
+		if tok.Type != hclsyntax.TokenIdent || string(tok.Bytes) != name {
+			continue
+		}
+		for _, tok := range tokens[i+1:] {
+			if tok.Type == hclsyntax.TokenComment {
+				comment += " " + strings.TrimSpace(string(tok.Bytes))
+			}
+			tok.Bytes = nil
+			tok.SpacesBefore = 0
+		}
+		tok.Type = hclsyntax.TokenComment
+		tok.Bytes = []byte(comment + "\n")
+		return
+	}
+}
+
+// tokenRef is a reference to a resource or data source attribute in the expression tokens, e.g. `data.foo.x[0].blk.attr`.
+type tokenRef struct {
+	scope ResourceScope
+	segs  []tokenRefSeg
+}
+
+type tokenRefSeg struct {
+	name string
+	// idx is the index of the name token
+	idx int
+	// indexed tells whether the segment is followed by an index (e.g. "[0]" or ".0")
+	indexed bool
+}
+
+// scanTokenRefs scans the expression tokens for the references to the resource, data source or ephemeral resource attributes.
+func scanTokenRefs(tokens hclwrite.Tokens) []tokenRef {
+	var refs []tokenRef
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].Type != hclsyntax.TokenIdent || (i > 0 && tokens[i-1].Type == hclsyntax.TokenDot) {
+			continue
+		}
+		names := []string{string(tokens[i].Bytes)}
+		switch names[0] {
+		case "var", "local", "module", "each", "count", "path", "terraform", "self":
+			continue
+		}
+		want := 2
+		if names[0] == "data" || names[0] == "ephemeral" {
+			want = 3
+		}
+		j := i
+		for len(names) < want && j+2 < len(tokens) && tokens[j+1].Type == hclsyntax.TokenDot && tokens[j+2].Type == hclsyntax.TokenIdent {
+			names = append(names, string(tokens[j+2].Bytes))
+			j += 2
+		}
+		if len(names) != want {
+			continue
+		}
+		ref := tokenRef{scope: ResourceScope{Type: names[0]}}
+		switch names[0] {
+		case "data":
+			ref.scope = ResourceScope{Type: names[1], IsDataSource: true}
+		case "ephemeral":
+			ref.scope = ResourceScope{Type: names[1], IsEphemeral: true}
+		}
+
+		k := skipTokenIndexes(tokens, j+1)
+		for k+1 < len(tokens) && tokens[k].Type == hclsyntax.TokenDot && tokens[k+1].Type == hclsyntax.TokenIdent {
+			seg := tokenRefSeg{name: string(tokens[k+1].Bytes), idx: k + 1}
+			nk := skipTokenIndexes(tokens, k+2)
+			seg.indexed = nk != k+2
+			ref.segs = append(ref.segs, seg)
+			k = nk
+		}
+		if len(ref.segs) != 0 {
+			refs = append(refs, ref)
+		}
+		i = k - 1
+	}
+	return refs
+}
+
+// skipTokenIndexes returns the position after the indexes (e.g. `[0]`, `["a"]`, `.0` or `[*]`) starting at the position.
+func skipTokenIndexes(tokens hclwrite.Tokens, k int) int {
+	for k < len(tokens) {
+		switch {
+		case tokens[k].Type == hclsyntax.TokenOBrack:
+			depth := 0
+			for ; k < len(tokens); k++ {
+				if tokens[k].Type == hclsyntax.TokenOBrack {
+					depth++
+				} else if tokens[k].Type == hclsyntax.TokenCBrack {
+					depth--
+					if depth == 0 {
+						break
+					}
+				}
+			}
+			k++
+		case tokens[k].Type == hclsyntax.TokenDot && k+1 < len(tokens) && tokens[k+1].Type == hclsyntax.TokenNumberLit:
+			k += 2
+		default:
+			return k
+		}
+	}
+	return k
+}
+
+// migrateExprTokens rewrites the references in the expression tokens, and returns the rewritten tokens if any is rewritten.
+func migrateExprTokens(tokens hclwrite.Tokens, m migration) (hclwrite.Tokens, bool) {
+	refs := scanTokenRefs(tokens)
+	if len(refs) == 0 {
+		return nil, false
+	}
+
+	renames := map[int]string{}
+	listifies := map[int]bool{}
+	for _, ref := range refs {
+		names := make([]string, len(ref.segs))
+		for i, seg := range ref.segs {
+			names[i] = seg.name
+		}
+		for _, r := range m.renames {
+			if scopeAddress(r.Scope) == scopeAddress(ref.scope) && len(r.From) <= len(names) && slices.Equal(r.From, names[:len(r.From)]) {
+				renames[ref.segs[len(r.From)-1].idx] = r.To[len(r.To)-1]
+			}
+		}
+		for _, change := range m.listifies {
+			if change.Scope == ref.scope && len(change.Path) <= len(names) && slices.Equal(change.Path, names[:len(change.Path)]) {
+				if seg := ref.segs[len(change.Path)-1]; !seg.indexed {
+					listifies[seg.idx] = true
+				}
+			}
+		}
+	}
+	if len(renames) == 0 && len(listifies) == 0 {
+		return nil, false
+	}
+
+	var out hclwrite.Tokens
+	for i, tok := range tokens {
+		ntok := *tok
+		if name, ok := renames[i]; ok {
+			ntok.Bytes = []byte(name)
+		}
+		out = append(out, &ntok)
+		if listifies[i] {
+			out = append(out,
+				&hclwrite.Token{Type: hclsyntax.TokenOBrack, Bytes: []byte("[")},
+				&hclwrite.Token{Type: hclsyntax.TokenNumberLit, Bytes: []byte("0")},
+				&hclwrite.Token{Type: hclsyntax.TokenCBrack, Bytes: []byte("]")},
+			)
+		}
+	}
+	return out, true
+}
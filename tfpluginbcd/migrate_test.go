@@ -0,0 +1,214 @@
+package tfpluginbcd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/magodo/tfpluginschema/schema"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestMigrateConfig(t *testing.T) {
+	osch := &ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"name":    {Type: cty.String, Required: true},
+						"old":     {Type: cty.String, Optional: true},
+						"removed": {Type: cty.Number, Optional: true},
+					},
+					NestedBlocks: map[string]*NestedBlockSchema{
+						"rule": {
+							NestingMode: schema.NestingSingle,
+							Block: &BlockSchema{
+								Attributes: map[string]*AttributeSchema{
+									"ip": {Type: cty.String, Optional: true},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	nsch := &ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"name": {Type: cty.String, Required: true},
+						"new":  {Type: cty.String, Optional: true},
+					},
+					NestedBlocks: map[string]*NestedBlockSchema{
+						"rule": {
+							NestingMode: schema.NestingList,
+							Block: &BlockSchema{
+								Attributes: map[string]*AttributeSchema{
+									"ip": {Type: cty.String, Optional: true},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	config := `resource "foo_resource" "a" {
+  name    = "a"
+  old     = "x" # keep me
+  removed = 1 # drop me
+  tags    = {a=1}
+  rule {
+    ip = "1.2.3.4"
+  }
+}
+
+resource "bar_resource" "b" {
+  old = "x"
+}
+
+output "o" {
+  value = [foo_resource.a.old, foo_resource.a.rule.ip, foo_resource.a.rule[0].ip, data.foo_resource.a.old, var.old]
+}
+`
+	// The formatting of the rest of the file is preserved
+	expect := `resource "foo_resource" "a" {
+  name    = "a"
+  new     = "x" # keep me
+  # removed = 1 (removed, as it is deleted from the provider schema) # drop me
+  tags    = {a=1}
+  rule {
+    ip = "1.2.3.4"
+  }
+}
+
+resource "bar_resource" "b" {
+  old = "x"
+}
+
+output "o" {
+  value = [foo_resource.a.new, foo_resource.a.rule[0].ip, foo_resource.a.rule[0].ip, data.foo_resource.a.old, var.old]
+}
+`
+	actual, err := migrateConfig([]byte(config), "main.tf", newMigration(osch, nsch), "foo")
+	require.NoError(t, err)
+	require.Equal(t, expect, string(actual))
+
+	// Unchanged content is not formatted
+	config = "resource \"bar_resource\" \"b\" {\n  old=\"x\"\n}\n"
+	actual, err = migrateConfig([]byte(config), "main.tf", newMigration(osch, nsch), "foo")
+	require.NoError(t, err)
+	require.Equal(t, config, string(actual))
+}
+
+func TestMigrateConfigDynamicBlock(t *testing.T) {
+	ruleBlock := &NestedBlockSchema{
+		NestingMode: schema.NestingList,
+		Optional:    true,
+		Block: &BlockSchema{
+			Attributes: map[string]*AttributeSchema{
+				"ip": {Type: cty.String, Optional: true},
+			},
+		},
+	}
+	osch := &ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					NestedBlocks: map[string]*NestedBlockSchema{"rule": ruleBlock},
+				},
+			},
+		},
+	}
+	nsch := &ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					NestedBlocks: map[string]*NestedBlockSchema{"network_rule": ruleBlock},
+				},
+			},
+		},
+	}
+
+	config := `resource "foo_resource" "a" {
+    rule {
+        ip = "1.1.1.1"
+    }
+    dynamic "rule" {
+        for_each = ["1.2.3.4"]
+        content {
+            ip = rule.value
+        }
+    }
+}
+`
+	expect := `resource "foo_resource" "a" {
+    network_rule {
+        ip = "1.1.1.1"
+    }
+    dynamic "network_rule" {
+        for_each = ["1.2.3.4"]
+        content {
+            ip = rule.value
+        }
+        iterator = rule
+    }
+}
+`
+	actual, err := migrateConfig([]byte(config), "main.tf", newMigration(osch, nsch), "foo")
+	require.NoError(t, err)
+	require.Equal(t, expect, string(actual))
+}
+
+func TestMigrateError(t *testing.T) {
+	osch := &ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"name":    {Type: cty.String, Required: true},
+						"removed": {Type: cty.Number, Optional: true},
+					},
+				},
+			},
+		},
+	}
+	nsch := &ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"name": {Type: cty.String, Required: true},
+					},
+				},
+			},
+		},
+	}
+	sdir := t.TempDir()
+	opath, npath := filepath.Join(sdir, "old.json"), filepath.Join(sdir, "new.json")
+	for path, sch := range map[string]*ProviderSchema{opath: osch, npath: nsch} {
+		b, err := json.Marshal(sch)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, b, 0644))
+	}
+
+	dir := t.TempDir()
+	valid := `resource "foo_resource" "a" {
+  name    = "a"
+  removed = 1
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.tf"), []byte(valid), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.tf"), []byte(`resource "foo_resource" "b" {`), 0644))
+
+	_, err := Migrate(opath, npath, dir, MigrateOpt{})
+	require.Error(t, err)
+	b, err := os.ReadFile(filepath.Join(dir, "a.tf"))
+	require.NoError(t, err)
+	require.Equal(t, valid, string(b))
+}
@@ -0,0 +1,282 @@
+package tfpluginbcd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/magodo/tfpluginschema/schema"
+	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/exp/slices"
+)
+
+// Rename is a deleted attribute (or block) that is linked to an added one, as a rename candidate.
+type Rename struct {
+	Scope   Scope
+	From    []string
+	To      []string
+	IsBlock bool
+}
+
+func (r Rename) String() string {
+	kind := "Attribute"
+	if r.IsBlock {
+		kind = "Block"
+	}
+	return fmt.Sprintf("%s %q of %s is renamed to %q", kind, strings.Join(r.From, "."), scopeString(r.Scope), strings.Join(r.To, "."))
+}
+
+// DetectRenames links the deleted attributes (or blocks) to the added ones as renames. A deletion and an addition are linked only if
+// they are of the same scope and the same parent path, with an identical structural schema (see memberShape), and neither of them
+// can be linked to another one. The schemas of the deletions and the additions are looked up from the old and new schemas.
+func DetectRenames(osch, nsch *ProviderSchema, changes []Change) []Rename {
+	type item struct {
+		scope   Scope
+		path    []string
+		isBlock bool
+		shape   []byte
+	}
+	var deletes, adds []item
+	for _, change := range changes {
+		var (
+			it    item
+			isAdd bool
+		)
+		switch change := change.(type) {
+		case AttributeChange:
+			if !change.IsAdd && !change.IsDelete {
+				continue
+			}
+			it = item{scope: change.Scope, path: change.Path}
+			isAdd = change.IsAdd
+		case BlockChange:
+			if !change.IsAdd && !change.IsDelete {
+				continue
+			}
+			it = item{scope: change.Scope, path: change.Path, isBlock: true}
+			isAdd = change.IsAdd
+		default:
+			continue
+		}
+		sch := osch
+		if isAdd {
+			sch = nsch
+		}
+		attr, nblk := sch.scopeBlock(it.scope).lookupMember(it.path)
+		var shape memberShape
+		switch {
+		case attr != nil && !it.isBlock:
+			shape = newAttributeShape(attr)
+		case nblk != nil && it.isBlock:
+			shape = newBlockShape(nblk)
+		default:
+			continue
+		}
+		b, err := json.Marshal(shape)
+		if err != nil {
+			continue
+		}
+		it.shape = b
+		if isAdd {
+			adds = append(adds, it)
+		} else {
+			deletes = append(deletes, it)
+		}
+	}
+
+	match := func(a, b item) bool {
+		return scopeAddress(a.scope) == scopeAddress(b.scope) &&
+			a.isBlock == b.isBlock &&
+			len(a.path) == len(b.path) &&
+			slices.Equal(a.path[:len(a.path)-1], b.path[:len(b.path)-1]) &&
+			bytes.Equal(a.shape, b.shape)
+	}
+	candidates := func(it item, items []item) []item {
+		var out []item
+		for _, other := range items {
+			if match(it, other) {
+				out = append(out, other)
+			}
+		}
+		return out
+	}
+
+	var renames []Rename
+	for _, del := range deletes {
+		addCands := candidates(del, adds)
+		if len(addCands) != 1 || len(candidates(addCands[0], deletes)) != 1 {
+			continue
+		}
+		renames = append(renames, Rename{
+			Scope:   del.scope,
+			From:    del.path,
+			To:      addCands[0].path,
+			IsBlock: del.isBlock,
+		})
+	}
+	return renames
+}
+
+// memberShape is the structural schema of an attribute or a nested block, including its members recursively. The docs are not
+// part of it, as a rename usually comes with a new description, and a deprecation of the old name.
+type memberShape struct {
+	Type        *cty.Type              `json:"type,omitempty"`
+	NestingMode schema.NestingMode     `json:"nesting_mode,omitempty"`
+	Required    bool                   `json:"required,omitempty"`
+	Optional    bool                   `json:"optional,omitempty"`
+	Computed    bool                   `json:"computed,omitempty"`
+	Sensitive   bool                   `json:"sensitive,omitempty"`
+	Attributes  map[string]memberShape `json:"attributes,omitempty"`
+	Blocks      map[string]memberShape `json:"blocks,omitempty"`
+}
+
+func newAttributeShape(attr *AttributeSchema) memberShape {
+	shape := memberShape{
+		Required:  attr.Required,
+		Optional:  attr.Optional,
+		Computed:  attr.Computed,
+		Sensitive: attr.Sensitive,
+	}
+	if nt := attr.NestedType; nt != nil {
+		shape.NestingMode = nt.NestingMode
+		shape.Attributes = newAttributesShape(nt.Attributes)
+	} else {
+		ty := attr.Type
+		shape.Type = &ty
+	}
+	return shape
+}
+
+func newAttributesShape(attrs map[string]*AttributeSchema) map[string]memberShape {
+	out := map[string]memberShape{}
+	for name, attr := range attrs {
+		if attr != nil {
+			out[name] = newAttributeShape(attr)
+		}
+	}
+	return out
+}
+
+func newBlockShape(nblk *NestedBlockSchema) memberShape {
+	shape := memberShape{
+		NestingMode: nblk.NestingMode,
+		Required:    nblk.Required,
+		Optional:    nblk.Optional,
+		Computed:    nblk.Computed,
+	}
+	if blk := nblk.Block; blk != nil {
+		shape.Attributes = newAttributesShape(blk.Attributes)
+		shape.Blocks = map[string]memberShape{}
+		for name, nblk := range blk.NestedBlocks {
+			if nblk != nil {
+				shape.Blocks[name] = newBlockShape(nblk)
+			}
+		}
+	}
+	return shape
+}
+
+// MigrationHints detects the renames between the old and new schemas, and emits the migration artifacts for each of the affected
+// resources, data sources or the provider config. These include the skeleton of the state upgrader (for the resources), and the
+// configuration rewrite hints for the users.
+func MigrationHints(opath, npath string) (string, error) {
+	osch, nsch, err := loadSchemas(opath, npath)
+	if err != nil {
+		return "", err
+	}
+	return migrationHints(osch, nsch), nil
+}
+
+func migrationHints(osch, nsch *ProviderSchema) string {
+	changes := Compare(osch, nsch)
+	renames := DetectRenames(osch, nsch, changes)
+
+	// Group the renames by the scopes (keyed by their addresses), in the order of the first appearance.
+	var scopes []Scope
+	renamesByScope := map[string][]Rename{}
+	for _, r := range renames {
+		key := scopeAddress(r.Scope)
+		if _, ok := renamesByScope[key]; !ok {
+			scopes = append(scopes, r.Scope)
+		}
+		renamesByScope[key] = append(renamesByScope[key], r)
+	}
+
+	var sections []string
+	for _, scope := range scopes {
+		renames := renamesByScope[scopeAddress(scope)]
+		var lines []string
+		lines = append(lines, "# "+scopeString(scope))
+
+		if rscope, ok := scope.(ResourceScope); ok && !rscope.IsDataSource && !rscope.IsEphemeral {
+			from := 0
+			if ores, ok := osch.ResourceSchemas[rscope.Type]; ok {
+				from = ores.SchemaVersion
+			}
+			to := from + 1
+			if nres, ok := nsch.ResourceSchemas[rscope.Type]; ok && nres.SchemaVersion > from {
+				to = nres.SchemaVersion
+			}
+			lines = append(lines, "", fmt.Sprintf("## State Upgrader (SchemaVersion: %d -> %d)", from, to), "", "```go")
+			lines = append(lines, stateUpgraderSkeleton(rscope.Type, from, to, renames)...)
+			lines = append(lines, "```")
+		}
+
+		lines = append(lines, "", "## Configuration", "")
+		for _, r := range renames {
+			kind := "attribute"
+			if r.IsBlock {
+				kind = "block"
+			}
+			lines = append(lines, fmt.Sprintf("- Rename the %s `%s` to `%s`", kind, strings.Join(r.From, "."), strings.Join(r.To, ".")))
+		}
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// stateUpgraderSkeleton returns the skeleton of the SDKv2 state upgrader for the renames of the resource.
+func stateUpgraderSkeleton(rt string, from, to int, renames []Rename) []string {
+	name := goIdentifier(rt)
+	fname := fmt.Sprintf("%sStateUpgradeV%dToV%d", name, from, to)
+	lines := []string{
+		"// In the resource schema:",
+		fmt.Sprintf("//   SchemaVersion: %d,", to),
+		"//   StateUpgraders: []schema.StateUpgrader{",
+		"//     {",
+		fmt.Sprintf("//       Version: %d,", from),
+		fmt.Sprintf("//       Type:    %sResourceV%d().CoreConfigSchema().ImpliedType(),", name, from),
+		fmt.Sprintf("//       Upgrade: %s,", fname),
+		"//     },",
+		"//   },",
+		"",
+		fmt.Sprintf("func %s(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {", fname),
+	}
+	for _, r := range renames {
+		if len(r.From) != 1 {
+			lines = append(lines, fmt.Sprintf("\t// TODO: rename %q to %q in the nested blocks", strings.Join(r.From, "."), strings.Join(r.To, ".")))
+			continue
+		}
+		lines = append(lines,
+			fmt.Sprintf("\tif v, ok := rawState[%q]; ok {", r.From[0]),
+			fmt.Sprintf("\t\trawState[%q] = v", r.To[0]),
+			fmt.Sprintf("\t\tdelete(rawState, %q)", r.From[0]),
+			"\t}",
+		)
+	}
+	lines = append(lines, "\treturn rawState, nil", "}")
+	return lines
+}
+
+// goIdentifier converts the snake case name to a lower camel case Go identifier, e.g. "foo_resource" to "fooResource".
+func goIdentifier(name string) string {
+	var out string
+	for i, seg := range strings.Split(name, "_") {
+		if i != 0 && seg != "" {
+			seg = strings.ToUpper(seg[:1]) + seg[1:]
+		}
+		out += seg
+	}
+	return out
+}
@@ -0,0 +1,172 @@
+package tfpluginbcd
+
+import (
+	"testing"
+
+	"github.com/magodo/tfpluginschema/schema"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestDetectRenames(t *testing.T) {
+	scope := ResourceScope{Type: "foo_resource"}
+	cases := []struct {
+		name   string
+		oblk   *BlockSchema
+		nblk   *BlockSchema
+		expect []Rename
+	}{
+		{
+			name: "Attribute renamed",
+			oblk: &BlockSchema{Attributes: map[string]*AttributeSchema{"old": {Type: cty.String, Optional: true}}},
+			nblk: &BlockSchema{Attributes: map[string]*AttributeSchema{"new": {Type: cty.String, Optional: true}}},
+			expect: []Rename{
+				{Scope: scope, From: []string{"old"}, To: []string{"new"}},
+			},
+		},
+		{
+			name: "Attribute renamed with different docs",
+			oblk: &BlockSchema{Attributes: map[string]*AttributeSchema{
+				"old": {Type: cty.String, Optional: true, Docs: Docs{Description: "The old name", Deprecated: true, DeprecationMessage: "Use new instead"}},
+			}},
+			nblk: &BlockSchema{Attributes: map[string]*AttributeSchema{
+				"new": {Type: cty.String, Optional: true, Docs: Docs{Description: "The new name"}},
+			}},
+			expect: []Rename{
+				{Scope: scope, From: []string{"old"}, To: []string{"new"}},
+			},
+		},
+		{
+			name: "Nested block renamed",
+			oblk: &BlockSchema{NestedBlocks: map[string]*NestedBlockSchema{"blk": {NestingMode: schema.NestingList, Block: &BlockSchema{
+				NestedBlocks: map[string]*NestedBlockSchema{"old": {NestingMode: schema.NestingList, Block: &BlockSchema{}}},
+			}}}},
+			nblk: &BlockSchema{NestedBlocks: map[string]*NestedBlockSchema{"blk": {NestingMode: schema.NestingList, Block: &BlockSchema{
+				NestedBlocks: map[string]*NestedBlockSchema{"new": {NestingMode: schema.NestingList, Block: &BlockSchema{}}},
+			}}}},
+			expect: []Rename{
+				{Scope: scope, From: []string{"blk", "old"}, To: []string{"blk", "new"}, IsBlock: true},
+			},
+		},
+		{
+			name: "Different schema",
+			oblk: &BlockSchema{Attributes: map[string]*AttributeSchema{"old": {Type: cty.String, Optional: true}}},
+			nblk: &BlockSchema{Attributes: map[string]*AttributeSchema{"new": {Type: cty.Number, Optional: true}}},
+		},
+		{
+			name: "Different block members",
+			oblk: &BlockSchema{NestedBlocks: map[string]*NestedBlockSchema{"old": {NestingMode: schema.NestingList, Block: &BlockSchema{
+				Attributes: map[string]*AttributeSchema{"a": {Type: cty.String, Optional: true}},
+			}}}},
+			nblk: &BlockSchema{NestedBlocks: map[string]*NestedBlockSchema{"new": {NestingMode: schema.NestingList, Block: &BlockSchema{
+				Attributes: map[string]*AttributeSchema{"a": {Type: cty.String, Required: true}},
+			}}}},
+		},
+		{
+			name: "Different parent path",
+			oblk: &BlockSchema{
+				Attributes:   map[string]*AttributeSchema{"old": {Type: cty.String, Optional: true}},
+				NestedBlocks: map[string]*NestedBlockSchema{"blk": {NestingMode: schema.NestingList, Block: &BlockSchema{}}},
+			},
+			nblk: &BlockSchema{
+				NestedBlocks: map[string]*NestedBlockSchema{"blk": {NestingMode: schema.NestingList, Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{"new": {Type: cty.String, Optional: true}},
+				}}},
+			},
+		},
+		{
+			name: "Ambiguous",
+			oblk: &BlockSchema{Attributes: map[string]*AttributeSchema{"old": {Type: cty.String, Optional: true}}},
+			nblk: &BlockSchema{Attributes: map[string]*AttributeSchema{
+				"new1": {Type: cty.String, Optional: true},
+				"new2": {Type: cty.String, Optional: true},
+			}},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			osch := &ProviderSchema{ResourceSchemas: map[string]*ResourceSchema{"foo_resource": {Block: tt.oblk}}}
+			nsch := &ProviderSchema{ResourceSchemas: map[string]*ResourceSchema{"foo_resource": {Block: tt.nblk}}}
+			require.Equal(t, tt.expect, DetectRenames(osch, nsch, Compare(osch, nsch)))
+		})
+	}
+}
+
+func TestMigrationHints(t *testing.T) {
+	osch := &ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"old": {Type: cty.String, Optional: true},
+					},
+				},
+			},
+		},
+		DataSourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"old": {Type: cty.String, Computed: true},
+					},
+				},
+			},
+		},
+	}
+	nsch := &ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"new": {Type: cty.String, Optional: true},
+					},
+				},
+			},
+		},
+		DataSourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"new": {Type: cty.String, Computed: true},
+					},
+				},
+			},
+		},
+	}
+
+	expect := "# data source foo_resource" + `
+
+## Configuration
+
+- Rename the attribute ` + "`old` to `new`" + `
+
+# resource foo_resource
+
+## State Upgrader (SchemaVersion: 0 -> 1)
+
+` + "```go" + `
+// In the resource schema:
+//   SchemaVersion: 1,
+//   StateUpgraders: []schema.StateUpgrader{
+//     {
+//       Version: 0,
+//       Type:    fooResourceResourceV0().CoreConfigSchema().ImpliedType(),
+//       Upgrade: fooResourceStateUpgradeV0ToV1,
+//     },
+//   },
+
+func fooResourceStateUpgradeV0ToV1(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if v, ok := rawState["old"]; ok {
+		rawState["new"] = v
+		delete(rawState, "old")
+	}
+	return rawState, nil
+}
+` + "```" + `
+
+## Configuration
+
+- Rename the attribute ` + "`old` to `new`"
+	require.Equal(t, expect, migrationHints(osch, nsch))
+}
@@ -0,0 +1,216 @@
+package tfpluginbcd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// BrokenReference is a path referenced by the constraint (e.g. conflicts_with) of an attribute or a block, which can't be resolved.
+type BrokenReference struct {
+	// Scope and Path of the attribute or block that declares the constraint.
+	Scope Scope
+	Path  []string
+	// IsBlock tells whether the referencing object is a block, otherwise, it is an attribute.
+	IsBlock bool
+
+	// Constraint is one of "conflicts_with", "required_with", "exactly_one_of" and "at_least_one_of".
+	Constraint string
+	// Reference is the referenced path, e.g. "foo.0.bar".
+	Reference string
+
+	// Cause is the change that broke the reference, i.e. the deletion of the referenced path or its ancestor, or the type change
+	// or conversion of its ancestor. It is nil if the reference can't be resolved in the old schema either.
+	Cause Change
+}
+
+func (r BrokenReference) String() string {
+	kind := "Attribute"
+	if r.IsBlock {
+		kind = "Block"
+	}
+	msg := fmt.Sprintf("%s %q of %s: %s references %q, which doesn't exist", kind, strings.Join(r.Path, "."), scopeString(r.Scope), r.Constraint, r.Reference)
+	if r.Cause != nil {
+		msg += " (broken by: " + r.Cause.String() + ")"
+	}
+	return msg
+}
+
+type ReferencesOpt struct {
+	// OnlyCaused only reports the references that broke because of a change between the old and new schemas.
+	OnlyCaused bool
+}
+
+func References(opath, npath string, opt ReferencesOpt) (string, error) {
+	osch, nsch, err := loadSchemas(opath, npath)
+	if err != nil {
+		return "", err
+	}
+	var out []string
+	for _, ref := range CheckReferences(osch, nsch) {
+		if opt.OnlyCaused && ref.Cause == nil {
+			continue
+		}
+		out = append(out, ref.String())
+	}
+	return strings.Join(out, "\n"), nil
+}
+
+// CheckReferences resolves each path referenced by the constraints in the new schema, and returns the broken references.
+// For the references that broke because of a change between the old and new schemas, the change is recorded as the cause.
+func CheckReferences(osch, nsch *ProviderSchema) []BrokenReference {
+	changes := Compare(osch, nsch)
+
+	var refs []BrokenReference
+	check := func(scope Scope, oroot, nroot *BlockSchema) {
+		refs = append(refs, checkBlockReferences(scope, nil, oroot, nroot, nroot, changes)...)
+	}
+
+	for _, item := range []struct {
+		scope Scope
+		o, n  *ConfigSchema
+	}{
+		{ProviderScope{}, osch.Provider, nsch.Provider},
+		{ProviderMetaScope{}, osch.ProviderMeta, nsch.ProviderMeta},
+	} {
+		if item.n == nil || item.n.Block == nil {
+			continue
+		}
+		var oroot *BlockSchema
+		if item.o != nil {
+			oroot = item.o.Block
+		}
+		check(item.scope, oroot, item.n.Block)
+	}
+	for _, item := range []struct {
+		om, nm       map[string]*ResourceSchema
+		isDataSource bool
+		isEphemeral  bool
+	}{
+		{osch.DataSourceSchemas, nsch.DataSourceSchemas, true, false},
+		{osch.ResourceSchemas, nsch.ResourceSchemas, false, false},
+		{osch.EphemeralResourceSchemas, nsch.EphemeralResourceSchemas, false, true},
+	} {
+		for _, rt := range mapSortedKeys(item.nm) {
+			nres := item.nm[rt]
+			if nres.Block == nil {
+				continue
+			}
+			var oroot *BlockSchema
+			if ores, ok := item.om[rt]; ok {
+				oroot = ores.Block
+			}
+			check(ResourceScope{Type: rt, IsDataSource: item.isDataSource, IsEphemeral: item.isEphemeral}, oroot, nres.Block)
+		}
+	}
+	return refs
+}
+
+func checkBlockReferences(scope Scope, path []string, oroot, nroot, blk *BlockSchema, changes []Change) []BrokenReference {
+	var refs []BrokenReference
+	check := func(path []string, isBlock bool, constraints map[string][]string) {
+		for _, constraint := range []string{"conflicts_with", "required_with", "exactly_one_of", "at_least_one_of"} {
+			for _, ref := range constraints[constraint] {
+				segs := referenceSegments(ref)
+				if resolveBlockPath(nroot, segs) {
+					continue
+				}
+				r := BrokenReference{
+					Scope:      scope,
+					Path:       path,
+					IsBlock:    isBlock,
+					Constraint: constraint,
+					Reference:  ref,
+				}
+				if resolveBlockPath(oroot, segs) {
+					r.Cause = referenceCause(scope, segs, changes)
+				}
+				refs = append(refs, r)
+			}
+		}
+	}
+
+	for _, name := range mapSortedKeys(blk.Attributes) {
+		attr := blk.Attributes[name]
+		npath := append(append([]string{}, path...), name)
+		check(npath, false, map[string][]string{
+			"conflicts_with":  attr.ConflictsWith,
+			"required_with":   attr.RequiredWith,
+			"exactly_one_of":  attr.ExactlyOneOf,
+			"at_least_one_of": attr.AtLeastOneOf,
+		})
+		if attr.NestedType != nil {
+			// The constraints of the nested attributes
+			refs = append(refs, checkBlockReferences(scope, npath, oroot, nroot, &BlockSchema{Attributes: attr.NestedType.Attributes}, changes)...)
+		}
+	}
+	for _, name := range mapSortedKeys(blk.NestedBlocks) {
+		nestedBlk := blk.NestedBlocks[name]
+		npath := append(append([]string{}, path...), name)
+		check(npath, true, map[string][]string{
+			"conflicts_with":  nestedBlk.ConflictsWith,
+			"required_with":   nestedBlk.RequiredWith,
+			"exactly_one_of":  nestedBlk.ExactlyOneOf,
+			"at_least_one_of": nestedBlk.AtLeastOneOf,
+		})
+		if nestedBlk.Block != nil {
+			refs = append(refs, checkBlockReferences(scope, npath, oroot, nroot, nestedBlk.Block, changes)...)
+		}
+	}
+	return refs
+}
+
+var indexSegmentRegexp = regexp.MustCompile(`^[0-9]+$`)
+
+// referenceSegments splits the referenced path into segments, with the list indexes (e.g. the "0" in "foo.0.bar") removed.
+func referenceSegments(ref string) []string {
+	var segs []string
+	for _, seg := range strings.Split(ref, ".") {
+		if indexSegmentRegexp.MatchString(seg) {
+			continue
+		}
+		segs = append(segs, seg)
+	}
+	return segs
+}
+
+// resolveBlockPath tells whether the path can be resolved to an attribute (including the nested attributes) or a nested block from the block.
+func resolveBlockPath(blk *BlockSchema, segs []string) bool {
+	attr, nestedBlk := blk.lookupMember(segs)
+	return attr != nil || nestedBlk != nil
+}
+
+// referenceCause returns the change that breaks the referenced path, i.e. the deletion of the referenced path or its ancestor,
+// or the type change or conversion of its ancestor (e.g. a nested attribute is changed to a primitive attribute).
+func referenceCause(scope Scope, segs []string, changes []Change) Change {
+	addr := scopeAddress(scope)
+	for _, change := range changes {
+		var (
+			cscope Scope
+			cpath  []string
+		)
+		switch change := change.(type) {
+		case AttributeChange:
+			isTypeChange := change.IsModify && change.Modification != nil && (change.Modification.Type != nil || change.Modification.NestingMode != nil)
+			if !change.IsDelete && !isTypeChange {
+				continue
+			}
+			cscope, cpath = change.Scope, change.Path
+		case BlockChange:
+			if !change.IsDelete {
+				continue
+			}
+			cscope, cpath = change.Scope, change.Path
+		case ConversionChange:
+			cscope, cpath = change.Scope, change.Path
+		default:
+			continue
+		}
+		if scopeAddress(cscope) == addr && len(cpath) <= len(segs) && slices.Equal(cpath, segs[:len(cpath)]) {
+			return change
+		}
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package tfpluginbcd
+
+import (
+	"testing"
+
+	"github.com/magodo/tfpluginschema/schema"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestCheckReferences(t *testing.T) {
+	osch := &ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"attr": {
+							Type:          cty.String,
+							Optional:      true,
+							ConflictsWith: []string{"network_rules.0.ip_rules", "not_exist"},
+						},
+					},
+					NestedBlocks: map[string]*NestedBlockSchema{
+						"network_rules": {
+							NestingMode: schema.NestingList,
+							Block: &BlockSchema{
+								Attributes: map[string]*AttributeSchema{
+									"ip_rules": {
+										Type:     cty.List(cty.String),
+										Optional: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	nsch := &ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"attr": {
+							Type:          cty.String,
+							Optional:      true,
+							ConflictsWith: []string{"network_rules.0.ip_rules", "not_exist"},
+						},
+					},
+					NestedBlocks: map[string]*NestedBlockSchema{
+						"network_rules": {
+							NestingMode: schema.NestingList,
+							Block: &BlockSchema{
+								Attributes: map[string]*AttributeSchema{
+									"ip_rule": {
+										Type:     cty.List(cty.String),
+										Optional: true,
+									},
+								},
+								NestedBlocks: map[string]*NestedBlockSchema{
+									"sub": {
+										NestingMode:  schema.NestingList,
+										RequiredWith: []string{"network_rules.0.ip_rule"},
+										Block:        &BlockSchema{},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	expect := []BrokenReference{
+		{
+			Scope:      ResourceScope{Type: "foo_resource"},
+			Path:       []string{"attr"},
+			Constraint: "conflicts_with",
+			Reference:  "network_rules.0.ip_rules",
+			Cause: AttributeChange{
+				Scope:    ResourceScope{Type: "foo_resource"},
+				Path:     []string{"network_rules", "ip_rules"},
+				IsDelete: true,
+				Previous: &Attribute{
+					Type:     cty.List(cty.String),
+					Optional: true,
+				},
+			},
+		},
+		{
+			Scope:      ResourceScope{Type: "foo_resource"},
+			Path:       []string{"attr"},
+			Constraint: "conflicts_with",
+			Reference:  "not_exist",
+		},
+	}
+	actual := CheckReferences(osch, nsch)
+	require.Equal(t, expect, actual)
+
+	require.Equal(t,
+		`Attribute "attr" of resource foo_resource: conflicts_with references "network_rules.0.ip_rules", which doesn't exist (broken by: Attribute "network_rules.ip_rules" of resource foo_resource is deleted)`,
+		actual[0].String(),
+	)
+}
+
+func TestCheckReferencesNestedAttributes(t *testing.T) {
+	nestedSettings := func() *AttributeSchema {
+		return &AttributeSchema{
+			Optional: true,
+			NestedType: &NestedTypeSchema{
+				NestingMode: schema.NestingList,
+				Attributes: map[string]*AttributeSchema{
+					"mode": {Type: cty.String, Optional: true},
+				},
+			},
+		}
+	}
+	osch := &ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"attr": {
+							Type:          cty.String,
+							Optional:      true,
+							ConflictsWith: []string{"config.0.mode", "settings.0.mode"},
+						},
+						"config":   nestedSettings(),
+						"settings": nestedSettings(),
+					},
+				},
+			},
+		},
+	}
+	nsch := &ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"attr": {
+							Type:          cty.String,
+							Optional:      true,
+							ConflictsWith: []string{"config.0.mode", "settings.0.mode"},
+						},
+						"config": {
+							Optional: true,
+							NestedType: &NestedTypeSchema{
+								NestingMode: schema.NestingList,
+								Attributes: map[string]*AttributeSchema{
+									"mode":  {Type: cty.String, Optional: true},
+									"other": {Type: cty.String, Optional: true, RequiredWith: []string{"config.0.missing"}},
+								},
+							},
+						},
+						"settings": {Type: cty.String, Optional: true},
+					},
+				},
+			},
+		},
+	}
+
+	var actual []string
+	for _, ref := range CheckReferences(osch, nsch) {
+		actual = append(actual, ref.String())
+	}
+	require.Equal(t, []string{
+		`Attribute "attr" of resource foo_resource: conflicts_with references "settings.0.mode", which doesn't exist (broken by: Attribute "settings" of resource foo_resource is changed: type: list of object -> string)`,
+		`Attribute "config.other" of resource foo_resource: required_with references "config.0.missing", which doesn't exist`,
+	}, actual)
+}
+
+func TestCheckReferencesProviderMeta(t *testing.T) {
+	osch := &ProviderSchema{
+		ProviderMeta: &ConfigSchema{
+			Block: &BlockSchema{
+				Attributes: map[string]*AttributeSchema{
+					"module_name": {Type: cty.String, Optional: true},
+					"version":     {Type: cty.String, Optional: true, RequiredWith: []string{"module_name"}},
+				},
+			},
+		},
+	}
+	nsch := &ProviderSchema{
+		ProviderMeta: &ConfigSchema{
+			Block: &BlockSchema{
+				Attributes: map[string]*AttributeSchema{
+					"version": {Type: cty.String, Optional: true, RequiredWith: []string{"module_name"}},
+				},
+			},
+		},
+	}
+
+	var actual []string
+	for _, ref := range CheckReferences(osch, nsch) {
+		actual = append(actual, ref.String())
+	}
+	require.Equal(t, []string{
+		`Attribute "version" of provider meta: required_with references "module_name", which doesn't exist (broken by: Attribute "module_name" of provider meta is deleted)`,
+	}, actual)
+}
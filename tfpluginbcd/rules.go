@@ -1,55 +1,340 @@
 package tfpluginbcd
 
+import (
+	"fmt"
+
+	"golang.org/x/exp/slices"
+)
+
+type RuleCategory string
+
+const (
+	// RuleCategoryBreaking is for rules that detect changes breaking the users' configurations or states.
+	RuleCategoryBreaking RuleCategory = "breaking"
+	// RuleCategorySecurity is for rules that detect changes that are not breaking, but are security concerns.
+	RuleCategorySecurity RuleCategory = "security"
+	// RuleCategoryDeprecation is for rules that detect changes of the deprecation status, which are the first step of a removal.
+	RuleCategoryDeprecation RuleCategory = "deprecation"
+)
+
 type Rule struct {
 	ID          string
+	Category    RuleCategory
 	Description string
 	Expr        string
 }
 
+// DefaultSecretNamePatterns are the regexp patterns used to tell whether an attribute name looks like a secret,
+// which are used by rules that reference the "input.secret_name_patterns".
+var DefaultSecretNamePatterns = []string{
+	`(?i)password`,
+	`(?i)secret`,
+	`(?i)(^|_)key$`,
+	`(?i)token`,
+}
+
 var Rules = map[string]Rule{
 	"R001": {
 		ID:          "R001",
+		Category:    RuleCategoryBreaking,
 		Description: "A resource is deleted",
-		Expr:        `c.kind == "resource"; not c.is_data_source; c.is_delete`,
+		Expr:        `c.kind == "resource"; not c.is_data_source; not c.is_ephemeral; c.is_delete`,
 	},
 	"R002": {
 		ID:          "R002",
+		Category:    RuleCategoryBreaking,
 		Description: "A data source is deleted",
 		Expr:        `c.kind == "resource"; c.is_data_source; c.is_delete`,
 	},
 	"R003": {
 		ID:          "R003",
-		Description: "An attribute is deleted",
-		Expr:        `c.kind == "attribute"; c.is_delete`,
+		Category:    RuleCategoryBreaking,
+		Description: "An attribute is deleted without prior deprecation",
+		Expr:        `c.kind == "attribute"; c.is_delete; not c.previous.deprecated`,
 	},
 	"R004": {
 		ID:          "R004",
+		Category:    RuleCategoryBreaking,
 		Description: "A block is deleted",
 		Expr:        `c.kind == "block"; c.is_delete`,
 	},
 	"R005": {
 		ID:          "R005",
+		Category:    RuleCategoryBreaking,
 		Description: "The type of an attribute is changed",
 		Expr:        `c.kind == "attribute"; c.is_modify; c.modification.type`,
 	},
 	"R006": {
 		ID:          "R006",
+		Category:    RuleCategoryBreaking,
 		Description: "An optional attribute is changed to be required",
 		Expr:        `c.kind == "attribute"; c.is_modify; c.modification.required.to == true`,
 	},
 	"R007": {
 		ID:          "R007",
+		Category:    RuleCategoryBreaking,
 		Description: "An optional block is changed to be required",
 		Expr:        `c.kind == "block"; c.is_modify; c.modification.required.to == true`,
 	},
 	"R008": {
 		ID:          "R008",
+		Category:    RuleCategoryBreaking,
 		Description: "A new required attribute is added",
 		Expr:        `c.kind == "attribute"; c.is_add; c.current.required == true`,
 	},
 	"R009": {
 		ID:          "R009",
+		Category:    RuleCategoryBreaking,
 		Description: "A new required block is added",
 		Expr:        `c.kind == "block"; c.is_add; c.current.required == true`,
 	},
+	"R010": {
+		ID:          "R010",
+		Category:    RuleCategoryBreaking,
+		Description: "The schema version of a resource is decreased",
+		Expr:        `c.kind == "resource"; c.is_modify; c.modification.schema_version.to < c.modification.schema_version.from`,
+	},
+	"R011": {
+		ID:          "R011",
+		Category:    RuleCategoryBreaking,
+		Description: "The type of a resource attribute is changed without bumping the schema version",
+		Expr:        `c.kind == "attribute"; c.is_modify; c.modification.type; c.scope.kind == "resource"; not c.scope.is_data_source; not c.scope.is_ephemeral; not schema_version_bumped(c.scope)`,
+	},
+	"R012": {
+		ID:          "R012",
+		Category:    RuleCategoryBreaking,
+		Description: "The nesting mode of a resource block is changed without bumping the schema version",
+		Expr:        `c.kind == "block"; c.is_modify; c.modification.nesting_mode; c.scope.kind == "resource"; not c.scope.is_data_source; not c.scope.is_ephemeral; not schema_version_bumped(c.scope)`,
+	},
+	"R013": {
+		ID:          "R013",
+		Category:    RuleCategoryBreaking,
+		Description: "A required or optional argument is deleted",
+		Expr:        `c.kind == "attribute"; c.is_delete; true in {c.previous.required, c.previous.optional}`,
+	},
+	"R014": {
+		ID:          "R014",
+		Category:    RuleCategoryBreaking,
+		Description: "A computed-only attribute is deleted",
+		Expr:        `c.kind == "attribute"; c.is_delete; c.previous.computed; not c.previous.required; not c.previous.optional`,
+	},
+	"R015": {
+		ID:          "R015",
+		Category:    RuleCategoryBreaking,
+		Description: "A resource or a function is deleted without prior deprecation",
+		Expr:        `c.kind in {"resource", "function"}; c.is_delete; not c.previous.deprecated`,
+	},
+	"R016": {
+		ID:          "R016",
+		Category:    RuleCategoryBreaking,
+		Description: "The nesting mode of a nested attribute is changed",
+		Expr:        `c.kind == "attribute"; c.is_modify; c.modification.nesting_mode`,
+	},
+	"R017": {
+		ID:          "R017",
+		Category:    RuleCategoryBreaking,
+		Description: "A block is converted to an attribute",
+		Expr:        `c.kind == "conversion"; c.is_block_to_attribute`,
+	},
+	"R018": {
+		ID:          "R018",
+		Category:    RuleCategoryBreaking,
+		Description: "A required or optional argument is converted to a block",
+		Expr:        `c.kind == "conversion"; c.is_attribute_to_block; true in {c.previous_attribute.required, c.previous_attribute.optional}`,
+	},
+	"R019": {
+		ID:          "R019",
+		Category:    RuleCategoryBreaking,
+		Description: "An ephemeral resource is deleted",
+		Expr:        `c.kind == "resource"; c.is_ephemeral; c.is_delete`,
+	},
+	"R020": {
+		ID:          "R020",
+		Category:    RuleCategoryBreaking,
+		Description: "A function is deleted",
+		Expr:        `c.kind == "function"; c.is_delete`,
+	},
+	"R021": {
+		ID:          "R021",
+		Category:    RuleCategoryBreaking,
+		Description: "The return type of a function is changed",
+		Expr:        `c.kind == "function"; c.is_modify; c.modification.return_type`,
+	},
+	"R022": {
+		ID:          "R022",
+		Category:    RuleCategoryBreaking,
+		Description: "A positional parameter is added to a function",
+		Expr:        `c.kind == "function_parameter"; not c.is_variadic; c.is_add`,
+	},
+	"R023": {
+		ID:          "R023",
+		Category:    RuleCategoryBreaking,
+		Description: "A parameter is deleted from a function",
+		Expr:        `c.kind == "function_parameter"; c.is_delete`,
+	},
+	"R024": {
+		ID:          "R024",
+		Category:    RuleCategoryBreaking,
+		Description: "The type of a function parameter is changed",
+		Expr:        `c.kind == "function_parameter"; c.is_modify; c.modification.type`,
+	},
+	"R025": {
+		ID:          "R025",
+		Category:    RuleCategoryBreaking,
+		Description: "A function parameter no longer allows null values",
+		Expr:        `c.kind == "function_parameter"; c.is_modify; c.modification.allow_null_value.to == false`,
+	},
+	"R026": {
+		ID:          "R026",
+		Category:    RuleCategoryBreaking,
+		Description: "An identity attribute is changed to be required for import",
+		Expr:        `c.kind == "attribute"; c.scope.kind == "identity"; c.is_modify; c.modification.required.to == true`,
+	},
+	"R027": {
+		ID:          "R027",
+		Category:    RuleCategoryBreaking,
+		Description: "An optional argument is changed to be computed-only",
+		Expr:        `c.kind == "attribute"; c.is_modify; c.modification.optional.from == true; c.modification.optional.to == false; not c.current.required`,
+	},
+	"S001": {
+		ID:          "S001",
+		Category:    RuleCategorySecurity,
+		Description: "A sensitive attribute is changed to be non-sensitive",
+		Expr:        `c.kind == "attribute"; c.is_modify; c.modification.sensitive.from == true; c.modification.sensitive.to == false`,
+	},
+	"S002": {
+		ID:          "S002",
+		Category:    RuleCategorySecurity,
+		Description: "A new attribute whose name looks like a secret is not sensitive",
+		Expr:        `c.kind == "attribute"; c.is_add; not c.current.sensitive; some p in input.secret_name_patterns; regex.match(p, c.path[count(c.path)-1])`,
+	},
+	"D001": {
+		ID:          "D001",
+		Category:    RuleCategoryDeprecation,
+		Description: "A resource, an attribute, a block or a function is deprecated",
+		Expr:        `c.kind in {"resource", "attribute", "block", "function"}; c.is_modify; c.modification.deprecated.to == true`,
+	},
+}
+
+// ProfileRule is a pre-defined rule selected by a profile, optionally tuned by extra Rego expressions.
+type ProfileRule struct {
+	ID string
+	// Expr is the extra Rego expressions that further restrict the changes matched by the rule.
+	Expr string
+}
+
+type Profile struct {
+	Name        string
+	Description string
+	Rules       []ProfileRule
+}
+
+// BuildRules returns the tuned rules of this profile.
+func (p Profile) BuildRules() ([]Rule, error) {
+	var rules []Rule
+	for _, pr := range p.Rules {
+		rule, ok := Rules[pr.ID]
+		if !ok {
+			return nil, fmt.Errorf("undefined rule %s in profile %s", pr.ID, p.Name)
+		}
+		if pr.Expr != "" {
+			rule.Expr = rule.Expr + "\n" + pr.Expr
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// excludeRules removes the rules of the excluded IDs, which must be defined in the catalog (i.e. Rules or LintRules).
+func excludeRules(rules []Rule, catalog map[string]Rule, excludes []string) ([]Rule, error) {
+	for _, id := range excludes {
+		if _, ok := catalog[id]; !ok {
+			return nil, fmt.Errorf("undefined rule to exclude: %s", id)
+		}
+	}
+	var out []Rule
+	for _, rule := range rules {
+		if slices.Contains(excludes, rule.ID) {
+			continue
+		}
+		out = append(out, rule)
+	}
+	return out, nil
+}
+
+const (
+	exprDataSourceOnly = `c.scope.kind == "resource"; c.scope.is_data_source`
+	exprResourceOnly   = `c.scope.kind in {"resource", "identity"}; not c.scope.is_data_source`
+)
+
+var Profiles = map[string]Profile{
+	"strict": {
+		Name:        "strict",
+		Description: "All the pre-defined rules",
+		Rules: []ProfileRule{
+			{ID: "R001"}, {ID: "R002"}, {ID: "R003"}, {ID: "R004"}, {ID: "R005"}, {ID: "R006"},
+			{ID: "R007"}, {ID: "R008"}, {ID: "R009"}, {ID: "R010"}, {ID: "R011"}, {ID: "R012"},
+			{ID: "R013"}, {ID: "R014"}, {ID: "R015"}, {ID: "R016"}, {ID: "R017"}, {ID: "R018"},
+			{ID: "R019"}, {ID: "R020"}, {ID: "R021"}, {ID: "R022"}, {ID: "R023"}, {ID: "R024"}, {ID: "R025"},
+			{ID: "R026"}, {ID: "R027"},
+			{ID: "S001"}, {ID: "S002"},
+			{ID: "D001"},
+		},
+	},
+	"ga": {
+		Name:        "ga",
+		Description: "The breaking change rules for a generally available provider",
+		Rules: []ProfileRule{
+			{ID: "R001"}, {ID: "R002"}, {ID: "R003"}, {ID: "R004"}, {ID: "R005"}, {ID: "R006"},
+			{ID: "R007"}, {ID: "R008"}, {ID: "R009"}, {ID: "R010"}, {ID: "R011"}, {ID: "R012"},
+			{ID: "R013"}, {ID: "R014"}, {ID: "R015"}, {ID: "R016"}, {ID: "R017"}, {ID: "R018"},
+			{ID: "R019"}, {ID: "R020"}, {ID: "R021"}, {ID: "R022"}, {ID: "R023"}, {ID: "R024"}, {ID: "R025"},
+			{ID: "R026"}, {ID: "R027"},
+		},
+	},
+	"resource": {
+		Name:        "resource",
+		Description: "The breaking change rules for resources (including ephemeral resources) and their identities",
+		Rules: []ProfileRule{
+			{ID: "R001"},
+			{ID: "R003", Expr: exprResourceOnly},
+			{ID: "R004", Expr: exprResourceOnly},
+			{ID: "R005", Expr: exprResourceOnly},
+			{ID: "R006", Expr: exprResourceOnly},
+			{ID: "R007", Expr: exprResourceOnly},
+			{ID: "R008", Expr: exprResourceOnly},
+			{ID: "R009", Expr: exprResourceOnly},
+			{ID: "R010"},
+			{ID: "R011"},
+			{ID: "R012"},
+			{ID: "R013", Expr: exprResourceOnly},
+			{ID: "R014", Expr: exprResourceOnly},
+			{ID: "R016", Expr: exprResourceOnly},
+			{ID: "R017", Expr: exprResourceOnly},
+			{ID: "R018", Expr: exprResourceOnly},
+			{ID: "R019"},
+			{ID: "R026"},
+			{ID: "R027", Expr: exprResourceOnly},
+		},
+	},
+	"data-source": {
+		Name:        "data-source",
+		Description: "The breaking change rules for data sources, where deleting any attribute (even a deprecated one) breaks downstream references, and an argument becoming required or computed-only breaks callers",
+		Rules: []ProfileRule{
+			// R013 and R014 are used instead of R003, which doesn't report the deletions of the deprecated attributes.
+			{ID: "R002"},
+			{ID: "R004", Expr: exprDataSourceOnly},
+			{ID: "R005", Expr: exprDataSourceOnly},
+			{ID: "R006", Expr: exprDataSourceOnly},
+			{ID: "R007", Expr: exprDataSourceOnly},
+			{ID: "R008", Expr: exprDataSourceOnly},
+			{ID: "R009", Expr: exprDataSourceOnly},
+			{ID: "R013", Expr: exprDataSourceOnly},
+			{ID: "R014", Expr: exprDataSourceOnly},
+			{ID: "R016", Expr: exprDataSourceOnly},
+			{ID: "R017", Expr: exprDataSourceOnly},
+			{ID: "R018", Expr: exprDataSourceOnly},
+			{ID: "R027", Expr: exprDataSourceOnly},
+		},
+	},
 }
@@ -0,0 +1,131 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestProfiles(t *testing.T) {
+	for name, profile := range Profiles {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, name, profile.Name)
+			rules, err := profile.BuildRules()
+			require.NoError(t, err)
+			// Ensure the tuned rules are valid Rego
+			_, err = Filter(context.TODO(), []Change{ProviderChange{IsAdd: true}}, rules, FilterOpt{})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestProfileStrictHasAllRules(t *testing.T) {
+	var ids []string
+	for _, rule := range Profiles["strict"].Rules {
+		ids = append(ids, rule.ID)
+	}
+	require.ElementsMatch(t, mapSortedKeys(Rules), ids)
+}
+
+func TestProfileGAHasAllBreakingRules(t *testing.T) {
+	var expect []string
+	for _, id := range mapSortedKeys(Rules) {
+		if Rules[id].Category == RuleCategoryBreaking {
+			expect = append(expect, id)
+		}
+	}
+	var ids []string
+	for _, rule := range Profiles["ga"].Rules {
+		ids = append(ids, rule.ID)
+	}
+	require.ElementsMatch(t, expect, ids)
+}
+
+func TestDeletionWithoutDeprecation(t *testing.T) {
+	docs := func(deprecated bool) Docs { return Docs{Deprecated: deprecated} }
+	var changes []Change
+	for _, deprecated := range []bool{false, true} {
+		changes = append(changes,
+			ResourceChange{Type: "foo_resource", IsDelete: true, Previous: &Resource{Docs: docs(deprecated)}},
+			FunctionChange{Name: "foo", IsDelete: true, Previous: &Function{ReturnType: cty.String, Docs: docs(deprecated)}},
+			AttributeChange{Scope: ResourceScope{Type: "bar_resource"}, Path: []string{"foo"}, IsDelete: true, Previous: &Attribute{Type: cty.String, Optional: true, Docs: docs(deprecated)}},
+		)
+	}
+	results, err := Filter(context.TODO(), changes, []Rule{Rules["R003"], Rules["R015"]}, FilterOpt{})
+	require.NoError(t, err)
+	var actual []string
+	for _, res := range results {
+		actual = append(actual, res.Rule+": "+res.Change.String())
+	}
+	// A single finding per deletion of a non-deprecated object.
+	require.Equal(t, []string{
+		`R003: Attribute "foo" of resource bar_resource is deleted`,
+		`R015: Resource foo_resource is deleted`,
+		`R015: Function "foo" is deleted`,
+	}, actual)
+}
+
+func TestProfileScopes(t *testing.T) {
+	deleted := func(scope Scope) Change {
+		return AttributeChange{Scope: scope, Path: []string{"foo"}, IsDelete: true, Previous: &Attribute{Type: cty.String, Optional: true, Docs: Docs{Deprecated: true}}}
+	}
+	newComputed := func(scope Scope) Change {
+		return AttributeChange{Scope: scope, Path: []string{"bar"}, IsAdd: true, Current: &Attribute{Type: cty.String, Computed: true}}
+	}
+	toComputed := func(scope Scope) Change {
+		return AttributeChange{
+			Scope:        scope,
+			Path:         []string{"baz"},
+			IsModify:     true,
+			Current:      &Attribute{Type: cty.String, Computed: true},
+			Modification: &AttributeModify{Optional: &Modification[bool]{From: true, To: false}, Computed: &Modification[bool]{From: false, To: true}},
+		}
+	}
+	var changes []Change
+	for _, scope := range []Scope{
+		ProviderScope{},
+		ProviderMetaScope{},
+		ResourceScope{Type: "foo_resource"},
+		ResourceScope{Type: "foo_data_source", IsDataSource: true},
+		IdentityScope{Type: "foo_resource"},
+	} {
+		changes = append(changes, deleted(scope), newComputed(scope), toComputed(scope))
+	}
+
+	cases := []struct {
+		profile string
+		expect  []string
+	}{
+		{
+			profile: "resource",
+			expect: []string{
+				`R013: Attribute "foo" of resource foo_resource is deleted`,
+				`R013: Attribute "foo" of identity of resource foo_resource is deleted`,
+				`R027: Attribute "baz" of resource foo_resource is changed: optional: true -> false, computed: false -> true`,
+				`R027: Attribute "baz" of identity of resource foo_resource is changed: optional: true -> false, computed: false -> true`,
+			},
+		},
+		{
+			profile: "data-source",
+			expect: []string{
+				`R013: Attribute "foo" of data source foo_data_source is deleted`,
+				`R027: Attribute "baz" of data source foo_data_source is changed: optional: true -> false, computed: false -> true`,
+			},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.profile, func(t *testing.T) {
+			rules, err := Profiles[tt.profile].BuildRules()
+			require.NoError(t, err)
+			results, err := Filter(context.TODO(), changes, rules, FilterOpt{})
+			require.NoError(t, err)
+			var actual []string
+			for _, res := range results {
+				actual = append(actual, res.Rule+": "+res.Change.String())
+			}
+			require.Equal(t, tt.expect, actual)
+		})
+	}
+}
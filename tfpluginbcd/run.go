@@ -2,47 +2,116 @@ package tfpluginbcd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
-
-	"github.com/magodo/tfpluginschema/schema"
 )
 
 type Opt struct {
-	Rules           []string
-	CustomRuleExprs []string
+	Profile            string
+	Rules              []string
+	CustomRuleExprs    []string
+	SecretNamePatterns []string
+	// ExcludeRules are the IDs of the pre-defined rules to exclude from the rules selected above (e.g. by the profile).
+	ExcludeRules []string
+
+	// Explain shows why each rule matched the change, see FilterOpt.
+	Explain bool
 }
 
 func Run(ctx context.Context, opath, npath string, opt Opt) (string, error) {
-	// Reading schemas
-	ob, err := os.ReadFile(opath)
+	osch, nsch, err := loadSchemas(opath, npath)
 	if err != nil {
-		return "", fmt.Errorf("reading the old schema file %s: %v", opath, err)
+		return "", err
 	}
-	var osch schema.ProviderSchema
-	if err := json.Unmarshal(ob, &osch); err != nil {
-		return "", fmt.Errorf("unmarshalling the old schema: %v", err)
+
+	changes, err := run(ctx, *osch, *nsch, opt)
+	if err != nil {
+		return "", err
 	}
-	nb, err := os.ReadFile(npath)
+	return strings.Join(changes, "\n"), nil
+}
+
+func run(ctx context.Context, osch, nsch ProviderSchema, opt Opt) ([]string, error) {
+	rules, err := buildRules(opt)
 	if err != nil {
-		return "", fmt.Errorf("reading the new schema file %s: %v", npath, err)
+		return nil, err
 	}
-	var nsch schema.ProviderSchema
-	if err := json.Unmarshal(nb, &nsch); err != nil {
-		return "", fmt.Errorf("unmarshalling the new schema: %v", err)
+	prules, err := PrepareRules(ctx, rules)
+	if err != nil {
+		return nil, fmt.Errorf("preparing rules: %v", err)
 	}
+	return runPrepared(ctx, osch, nsch, prules, opt)
+}
 
-	changes, err := run(ctx, osch, nsch, opt)
+// runPrepared is the same as run, but with the prepared rules, so that the rules are only compiled once for multiple runs.
+func runPrepared(ctx context.Context, osch, nsch ProviderSchema, prules *PreparedRules, opt Opt) ([]string, error) {
+	results, err := prules.Filter(ctx, Compare(&osch, &nsch), opt.filterOpt())
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("filtering: %v", err)
 	}
-	return strings.Join(changes, "\n"), nil
+
+	var output []string
+	for _, res := range results {
+		if res.Rule == "" {
+			output = append(output, res.Change.String())
+		} else {
+			output = append(output, fmt.Sprintf("[%s] %s", res.Rule, res.Change.String()))
+		}
+		if res.Explanation != nil {
+			output = append(output, explanationLines(res.Explanation)...)
+		}
+	}
+	return output, nil
 }
 
-func run(ctx context.Context, osch, nsch schema.ProviderSchema, opt Opt) ([]string, error) {
+func explanationLines(exp *Explanation) []string {
+	indent := func(s, prefix string) []string {
+		var lines []string
+		for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+			lines = append(lines, prefix+strings.TrimSpace(line))
+		}
+		return lines
+	}
+	out := []string{"  Rule:"}
+	out = append(out, indent(exp.Expr, "    ")...)
+	out = append(out, "  Change:")
+	for _, line := range strings.Split(exp.Input, "\n") {
+		out = append(out, "    "+line)
+	}
+	out = append(out, "  Trace:")
+	for _, step := range exp.Trace {
+		out = append(out, indent(step, "    - ")...)
+	}
+	return out
+}
+
+// hasRules tells whether any rule is selected by the option.
+func (opt Opt) hasRules() bool {
+	return opt.Profile != "" || len(opt.Rules) != 0 || len(opt.CustomRuleExprs) != 0
+}
+
+func (opt Opt) filterOpt() FilterOpt {
+	return FilterOpt{
+		SecretNamePatterns: opt.SecretNamePatterns,
+		Explain:            opt.Explain,
+	}
+}
+
+// buildRules builds the rules selected by the option, including the rules of the profile, the pre-defined rules and the custom rules.
+func buildRules(opt Opt) ([]Rule, error) {
 	var rules []Rule
+	if opt.Profile != "" {
+		profile, ok := Profiles[opt.Profile]
+		if !ok {
+			return nil, fmt.Errorf("undefined profile: %s", opt.Profile)
+		}
+		prules, err := profile.BuildRules()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, prules...)
+	}
 	for _, name := range opt.Rules {
 		rule, ok := Rules[name]
 		if !ok {
@@ -56,18 +125,29 @@ func run(ctx context.Context, osch, nsch schema.ProviderSchema, opt Opt) ([]stri
 			Expr: expr,
 		})
 	}
-	results, err := Filter(ctx, Compare(&osch, &nsch), rules)
+	return excludeRules(rules, Rules, opt.ExcludeRules)
+}
+
+func loadSchemas(opath, npath string) (*ProviderSchema, *ProviderSchema, error) {
+	osch, err := loadSchema(opath)
 	if err != nil {
-		return nil, fmt.Errorf("filtering: %v", err)
+		return nil, nil, fmt.Errorf("loading the old schema: %v", err)
 	}
+	nsch, err := loadSchema(npath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading the new schema: %v", err)
+	}
+	return osch, nsch, nil
+}
 
-	var output []string
-	for _, res := range results {
-		if res.Rule == "" {
-			output = append(output, res.Change.String())
-		} else {
-			output = append(output, fmt.Sprintf("[%s] %s", res.Rule, res.Change.String()))
-		}
+func loadSchema(path string) (*ProviderSchema, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading the schema file %s: %v", path, err)
 	}
-	return output, nil
+	sch, err := unmarshalSchema(b)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalling the schema file %s: %v", path, err)
+	}
+	return sch, nil
 }
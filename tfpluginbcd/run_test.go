@@ -13,7 +13,7 @@ func TestRun(t *testing.T) {
 	cases := []struct {
 		name       string
 		opt        Opt
-		osch, nsch schema.ProviderSchema
+		osch, nsch ProviderSchema
 		filtN      int
 		hasError   bool
 	}{
@@ -29,12 +29,12 @@ func TestRun(t *testing.T) {
 			opt: Opt{
 				Rules: []string{"R001"},
 			},
-			osch: schema.ProviderSchema{
-				ResourceSchemas: map[string]*schema.Resource{
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
 					"foo_resource": {},
 				},
 			},
-			nsch:  schema.ProviderSchema{},
+			nsch:  ProviderSchema{},
 			filtN: 1,
 		},
 		{
@@ -42,12 +42,12 @@ func TestRun(t *testing.T) {
 			opt: Opt{
 				Rules: []string{"R002"},
 			},
-			osch: schema.ProviderSchema{
-				DataSourceSchemas: map[string]*schema.Resource{
+			osch: ProviderSchema{
+				DataSourceSchemas: map[string]*ResourceSchema{
 					"foo_resource": {},
 				},
 			},
-			nsch:  schema.ProviderSchema{},
+			nsch:  ProviderSchema{},
 			filtN: 1,
 		},
 		{
@@ -55,24 +55,26 @@ func TestRun(t *testing.T) {
 			opt: Opt{
 				Rules: []string{"R003"},
 			},
-			osch: schema.ProviderSchema{
-				ResourceSchemas: map[string]*schema.Resource{
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
 					"foo_resource": {
-						Block: &schema.Block{
-							Attributes: map[string]*schema.Attribute{
-								"old_only_attr": {},
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
+								"old_only_attr": {
+									Type: cty.String,
+								},
 							},
-							NestedBlocks: map[string]*schema.NestedBlock{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
 						},
 					},
 				},
 			},
-			nsch: schema.ProviderSchema{
-				ResourceSchemas: map[string]*schema.Resource{
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
 					"foo_resource": {
-						Block: &schema.Block{
-							Attributes:   map[string]*schema.Attribute{},
-							NestedBlocks: map[string]*schema.NestedBlock{},
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
 						},
 					},
 				},
@@ -84,24 +86,24 @@ func TestRun(t *testing.T) {
 			opt: Opt{
 				Rules: []string{"R004"},
 			},
-			osch: schema.ProviderSchema{
-				ResourceSchemas: map[string]*schema.Resource{
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
 					"foo_resource": {
-						Block: &schema.Block{
-							Attributes: map[string]*schema.Attribute{},
-							NestedBlocks: map[string]*schema.NestedBlock{
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{
 								"old_only_blk": {},
 							},
 						},
 					},
 				},
 			},
-			nsch: schema.ProviderSchema{
-				ResourceSchemas: map[string]*schema.Resource{
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
 					"foo_resource": {
-						Block: &schema.Block{
-							Attributes:   map[string]*schema.Attribute{},
-							NestedBlocks: map[string]*schema.NestedBlock{},
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
 						},
 					},
 				},
@@ -113,30 +115,30 @@ func TestRun(t *testing.T) {
 			opt: Opt{
 				Rules: []string{"R005"},
 			},
-			osch: schema.ProviderSchema{
-				ResourceSchemas: map[string]*schema.Resource{
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
 					"foo_resource": {
-						Block: &schema.Block{
-							Attributes: map[string]*schema.Attribute{
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
 								"attr": {
 									Type: cty.Bool,
 								},
 							},
-							NestedBlocks: map[string]*schema.NestedBlock{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
 						},
 					},
 				},
 			},
-			nsch: schema.ProviderSchema{
-				ResourceSchemas: map[string]*schema.Resource{
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
 					"foo_resource": {
-						Block: &schema.Block{
-							Attributes: map[string]*schema.Attribute{
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
 								"attr": {
 									Type: cty.String,
 								},
 							},
-							NestedBlocks: map[string]*schema.NestedBlock{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
 						},
 					},
 				},
@@ -148,31 +150,31 @@ func TestRun(t *testing.T) {
 			opt: Opt{
 				Rules: []string{"R006"},
 			},
-			osch: schema.ProviderSchema{
-				ResourceSchemas: map[string]*schema.Resource{
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
 					"foo_resource": {
-						Block: &schema.Block{
-							Attributes: map[string]*schema.Attribute{
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
 								"attr": {
 									Type: cty.Bool,
 								},
 							},
-							NestedBlocks: map[string]*schema.NestedBlock{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
 						},
 					},
 				},
 			},
-			nsch: schema.ProviderSchema{
-				ResourceSchemas: map[string]*schema.Resource{
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
 					"foo_resource": {
-						Block: &schema.Block{
-							Attributes: map[string]*schema.Attribute{
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
 								"attr": {
 									Type:     cty.Bool,
 									Required: true,
 								},
 							},
-							NestedBlocks: map[string]*schema.NestedBlock{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
 						},
 					},
 				},
@@ -184,16 +186,16 @@ func TestRun(t *testing.T) {
 			opt: Opt{
 				Rules: []string{"R007"},
 			},
-			osch: schema.ProviderSchema{
-				ResourceSchemas: map[string]*schema.Resource{
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
 					"foo_resource": {
-						Block: &schema.Block{
-							Attributes: map[string]*schema.Attribute{},
-							NestedBlocks: map[string]*schema.NestedBlock{
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{
 								"blk": {
-									Block: &schema.Block{
-										Attributes:   map[string]*schema.Attribute{},
-										NestedBlocks: map[string]*schema.NestedBlock{},
+									Block: &BlockSchema{
+										Attributes:   map[string]*AttributeSchema{},
+										NestedBlocks: map[string]*NestedBlockSchema{},
 									},
 								},
 							},
@@ -201,17 +203,17 @@ func TestRun(t *testing.T) {
 					},
 				},
 			},
-			nsch: schema.ProviderSchema{
-				ResourceSchemas: map[string]*schema.Resource{
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
 					"foo_resource": {
-						Block: &schema.Block{
-							Attributes: map[string]*schema.Attribute{},
-							NestedBlocks: map[string]*schema.NestedBlock{
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{
 								"blk": {
 									Required: true,
-									Block: &schema.Block{
-										Attributes:   map[string]*schema.Attribute{},
-										NestedBlocks: map[string]*schema.NestedBlock{},
+									Block: &BlockSchema{
+										Attributes:   map[string]*AttributeSchema{},
+										NestedBlocks: map[string]*NestedBlockSchema{},
 									},
 								},
 							},
@@ -226,27 +228,27 @@ func TestRun(t *testing.T) {
 			opt: Opt{
 				Rules: []string{"R008"},
 			},
-			osch: schema.ProviderSchema{
-				ResourceSchemas: map[string]*schema.Resource{
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
 					"foo_resource": {
-						Block: &schema.Block{
-							Attributes:   map[string]*schema.Attribute{},
-							NestedBlocks: map[string]*schema.NestedBlock{},
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
 						},
 					},
 				},
 			},
-			nsch: schema.ProviderSchema{
-				ResourceSchemas: map[string]*schema.Resource{
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
 					"foo_resource": {
-						Block: &schema.Block{
-							Attributes: map[string]*schema.Attribute{
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
 								"attr": {
 									Type:     cty.Bool,
 									Required: true,
 								},
 							},
-							NestedBlocks: map[string]*schema.NestedBlock{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
 						},
 					},
 				},
@@ -258,27 +260,27 @@ func TestRun(t *testing.T) {
 			opt: Opt{
 				Rules: []string{"R009"},
 			},
-			osch: schema.ProviderSchema{
-				ResourceSchemas: map[string]*schema.Resource{
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
 					"foo_resource": {
-						Block: &schema.Block{
-							Attributes:   map[string]*schema.Attribute{},
-							NestedBlocks: map[string]*schema.NestedBlock{},
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
 						},
 					},
 				},
 			},
-			nsch: schema.ProviderSchema{
-				ResourceSchemas: map[string]*schema.Resource{
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
 					"foo_resource": {
-						Block: &schema.Block{
-							Attributes: map[string]*schema.Attribute{},
-							NestedBlocks: map[string]*schema.NestedBlock{
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{
 								"blk": {
 									Required: true,
-									Block: &schema.Block{
-										Attributes:   map[string]*schema.Attribute{},
-										NestedBlocks: map[string]*schema.NestedBlock{},
+									Block: &BlockSchema{
+										Attributes:   map[string]*AttributeSchema{},
+										NestedBlocks: map[string]*NestedBlockSchema{},
 									},
 								},
 							},
@@ -288,16 +290,552 @@ func TestRun(t *testing.T) {
 			},
 			filtN: 1,
 		},
+		{
+			name: "rule10",
+			opt: Opt{
+				Rules: []string{"R010"},
+			},
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						SchemaVersion: 2,
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						SchemaVersion: 1,
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			filtN: 1,
+		},
+		{
+			name: "rule11",
+			opt: Opt{
+				Rules: []string{"R011"},
+			},
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						SchemaVersion: 1,
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
+								"attr": {
+									Type: cty.Bool,
+								},
+							},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						SchemaVersion: 1,
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
+								"attr": {
+									Type: cty.String,
+								},
+							},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			filtN: 1,
+		},
+		{
+			name: "rule11 with schema version bumped",
+			opt: Opt{
+				Rules: []string{"R011"},
+			},
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						SchemaVersion: 1,
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
+								"attr": {
+									Type: cty.Bool,
+								},
+							},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						SchemaVersion: 2,
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
+								"attr": {
+									Type: cty.String,
+								},
+							},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			filtN: 0,
+		},
+		{
+			name: "rule12",
+			opt: Opt{
+				Rules: []string{"R012"},
+			},
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						SchemaVersion: 1,
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{
+								"blk": {
+									NestingMode: schema.NestingList,
+									Block: &BlockSchema{
+										Attributes:   map[string]*AttributeSchema{},
+										NestedBlocks: map[string]*NestedBlockSchema{},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						SchemaVersion: 1,
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{
+								"blk": {
+									NestingMode: schema.NestingSet,
+									Block: &BlockSchema{
+										Attributes:   map[string]*AttributeSchema{},
+										NestedBlocks: map[string]*NestedBlockSchema{},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			filtN: 1,
+		},
+		{
+			name: "rule12 with schema version bumped",
+			opt: Opt{
+				Rules: []string{"R012"},
+			},
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						SchemaVersion: 1,
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{
+								"blk": {
+									NestingMode: schema.NestingList,
+									Block: &BlockSchema{
+										Attributes:   map[string]*AttributeSchema{},
+										NestedBlocks: map[string]*NestedBlockSchema{},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						SchemaVersion: 2,
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{
+								"blk": {
+									NestingMode: schema.NestingSet,
+									Block: &BlockSchema{
+										Attributes:   map[string]*AttributeSchema{},
+										NestedBlocks: map[string]*NestedBlockSchema{},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			filtN: 0,
+		},
+		{
+			name: "rule13",
+			opt: Opt{
+				Rules: []string{"R013"},
+			},
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
+								"required_attr": {
+									Type:     cty.String,
+									Required: true,
+								},
+								"optional_attr": {
+									Type:     cty.String,
+									Optional: true,
+								},
+								"optional_computed_attr": {
+									Type:     cty.String,
+									Optional: true,
+									Computed: true,
+								},
+								"computed_attr": {
+									Type:     cty.String,
+									Computed: true,
+								},
+							},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			filtN: 3,
+		},
+		{
+			name: "rule14",
+			opt: Opt{
+				Rules: []string{"R014"},
+			},
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
+								"required_attr": {
+									Type:     cty.String,
+									Required: true,
+								},
+								"optional_attr": {
+									Type:     cty.String,
+									Optional: true,
+								},
+								"optional_computed_attr": {
+									Type:     cty.String,
+									Optional: true,
+									Computed: true,
+								},
+								"computed_attr": {
+									Type:     cty.String,
+									Computed: true,
+								},
+							},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			filtN: 1,
+		},
+		{
+			name: "security rule1",
+			opt: Opt{
+				Rules: []string{"S001"},
+			},
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
+								"attr": {
+									Type:      cty.String,
+									Sensitive: true,
+								},
+							},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
+								"attr": {
+									Type: cty.String,
+								},
+							},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			filtN: 1,
+		},
+		{
+			name: "security rule2",
+			opt: Opt{
+				Rules: []string{"S002"},
+			},
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
+								"admin_password": {
+									Type: cty.String,
+								},
+								"access_key": {
+									Type: cty.String,
+								},
+								"token": {
+									Type:      cty.String,
+									Sensitive: true,
+								},
+								"key_vault_id": {
+									Type: cty.String,
+								},
+							},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			filtN: 2,
+		},
+		{
+			name: "security rule2 with custom patterns",
+			opt: Opt{
+				Rules:              []string{"S002"},
+				SecretNamePatterns: []string{"^token$"},
+			},
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
+								"admin_password": {
+									Type: cty.String,
+								},
+								"token": {
+									Type: cty.String,
+								},
+							},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			filtN: 1,
+		},
+		{
+			name: "not defined profile",
+			opt: Opt{
+				Profile: "xxx",
+			},
+			hasError: true,
+		},
+		{
+			name: "profile data source ignores resource",
+			opt: Opt{
+				Profile: "data-source",
+			},
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
+								"attr": {
+									Type: cty.String,
+								},
+							},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			filtN: 0,
+		},
+		{
+			name: "profile data source",
+			opt: Opt{
+				Profile: "data-source",
+			},
+			osch: ProviderSchema{
+				DataSourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
+								"attr": {
+									Type:     cty.String,
+									Optional: true,
+								},
+							},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			nsch: ProviderSchema{
+				DataSourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			filtN: 1,
+		},
+		{
+			name: "profile data source with excluded rule",
+			opt: Opt{
+				Profile:      "data-source",
+				ExcludeRules: []string{"R013"},
+			},
+			osch: ProviderSchema{
+				DataSourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
+								"attr": {
+									Type:     cty.String,
+									Optional: true,
+								},
+							},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			nsch: ProviderSchema{
+				DataSourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			filtN: 0,
+		},
+		{
+			name: "undefined rule to exclude",
+			opt: Opt{
+				ExcludeRules: []string{"Rxxx"},
+			},
+			hasError: true,
+		},
+		{
+			name: "profile resource ignores data source",
+			opt: Opt{
+				Profile: "resource",
+			},
+			osch: ProviderSchema{
+				DataSourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						Block: &BlockSchema{
+							Attributes: map[string]*AttributeSchema{
+								"attr": {
+									Type: cty.String,
+								},
+							},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			nsch: ProviderSchema{
+				DataSourceSchemas: map[string]*ResourceSchema{
+					"foo_resource": {
+						Block: &BlockSchema{
+							Attributes:   map[string]*AttributeSchema{},
+							NestedBlocks: map[string]*NestedBlockSchema{},
+						},
+					},
+				},
+			},
+			filtN: 0,
+		},
 		{
 			name: "rule1 no match",
 			opt: Opt{
 				Rules: []string{"R001"},
 			},
-			osch: schema.ProviderSchema{
-				ResourceSchemas: map[string]*schema.Resource{},
+			osch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{},
 			},
-			nsch: schema.ProviderSchema{
-				ResourceSchemas: map[string]*schema.Resource{},
+			nsch: ProviderSchema{
+				ResourceSchemas: map[string]*ResourceSchema{},
 			},
 			filtN: 0,
 		},
@@ -0,0 +1,380 @@
+package tfpluginbcd
+
+import (
+	"encoding/json"
+
+	"github.com/magodo/tfpluginschema/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ProviderSchema is the schema of a provider, in the same JSON shape as the one of the tfpluginschema package. Besides, it models
+// the information that isn't modelled by the tfpluginschema package, e.g. the descriptions, the deprecations, the nested
+// attributes, the ephemeral resources, the provider-defined functions, the provider meta and the resource identities.
+type ProviderSchema struct {
+	Provider          *ConfigSchema              `json:"provider,omitempty"`
+	ResourceSchemas   map[string]*ResourceSchema `json:"resource_schemas,omitempty"`
+	DataSourceSchemas map[string]*ResourceSchema `json:"data_source_schemas,omitempty"`
+
+	EphemeralResourceSchemas map[string]*ResourceSchema `json:"ephemeral_resource_schemas,omitempty"`
+	Functions                map[string]*Function       `json:"functions,omitempty"`
+	ProviderMeta             *ConfigSchema              `json:"provider_meta,omitempty"`
+	ResourceIdentitySchemas  map[string]*IdentitySchema `json:"resource_identity_schemas,omitempty"`
+}
+
+// ConfigSchema is the schema of the provider config or the provider meta.
+type ConfigSchema struct {
+	Block *BlockSchema `json:"block,omitempty"`
+}
+
+type ResourceSchema struct {
+	SchemaVersion int          `json:"schema_version,omitempty"`
+	Block         *BlockSchema `json:"block,omitempty"`
+}
+
+// BlockSchema is the schema of a block, whose docs are also the docs of the resource or the nested block that it belongs to.
+type BlockSchema struct {
+	Attributes   map[string]*AttributeSchema   `json:"attributes,omitempty"`
+	NestedBlocks map[string]*NestedBlockSchema `json:"block_types,omitempty"`
+	Docs
+}
+
+type NestedBlockSchema struct {
+	NestingMode schema.NestingMode `json:"nesting_mode,omitempty"`
+	Block       *BlockSchema       `json:"block,omitempty"`
+
+	Required bool `json:"required,omitempty"`
+	Optional bool `json:"optional,omitempty"`
+	Computed bool `json:"computed,omitempty"`
+	ForceNew bool `json:"force_new,omitempty"`
+
+	ConflictsWith []string `json:"conflicts_with,omitempty"`
+	ExactlyOneOf  []string `json:"exactly_one_of,omitempty"`
+	AtLeastOneOf  []string `json:"at_least_one_of,omitempty"`
+	RequiredWith  []string `json:"required_with,omitempty"`
+
+	MinItems int `json:"min_items,omitempty"`
+	MaxItems int `json:"max_items,omitempty"`
+}
+
+type AttributeSchema struct {
+	// Type is absent for a nested attribute, whose type is implied by its NestedType instead (see ImpliedType).
+	Type cty.Type `json:"type,omitempty"`
+
+	// NestedType is non-nil only for a nested attribute (introduced by the plugin framework).
+	NestedType *NestedTypeSchema `json:"nested_type,omitempty"`
+
+	Required bool `json:"required,omitempty"`
+	Optional bool `json:"optional,omitempty"`
+	Computed bool `json:"computed,omitempty"`
+	ForceNew bool `json:"force_new,omitempty"`
+
+	Default   interface{} `json:"default,omitempty"`
+	Sensitive bool        `json:"sensitive,omitempty"`
+
+	ConflictsWith []string `json:"conflicts_with,omitempty"`
+	ExactlyOneOf  []string `json:"exactly_one_of,omitempty"`
+	AtLeastOneOf  []string `json:"at_least_one_of,omitempty"`
+	RequiredWith  []string `json:"required_with,omitempty"`
+
+	Docs
+}
+
+// NestedTypeSchema is the nested type of a nested attribute, whose attributes are complete attribute schemas that can be nested further.
+type NestedTypeSchema struct {
+	NestingMode schema.NestingMode          `json:"nesting_mode,omitempty"`
+	Attributes  map[string]*AttributeSchema `json:"attributes,omitempty"`
+}
+
+// IdentitySchema is the identity schema of a resource, which is used by the import blocks to identify the remote object.
+type IdentitySchema struct {
+	Attributes map[string]*IdentityAttributeSchema `json:"attributes,omitempty"`
+}
+
+type IdentityAttributeSchema struct {
+	Type              cty.Type `json:"type"`
+	RequiredForImport bool     `json:"required_for_import,omitempty"`
+	OptionalForImport bool     `json:"optional_for_import,omitempty"`
+	Description       string   `json:"description,omitempty"`
+}
+
+// Docs is the documentation related information of a resource, an attribute, a block or a function.
+type Docs struct {
+	Description        string `json:"description"`
+	Deprecated         bool   `json:"deprecated"`
+	DeprecationMessage string `json:"deprecation_message"`
+}
+
+// normalize regards the object with a deprecation message as deprecated.
+func (d *Docs) normalize() {
+	if d.DeprecationMessage != "" {
+		d.Deprecated = true
+	}
+}
+
+// ImpliedType returns the type of the attribute. For a nested attribute, it is the type implied by its nested type, which is
+// how Terraform regards the value of a nested attribute.
+func (a *AttributeSchema) ImpliedType() cty.Type {
+	nt := a.NestedType
+	if nt == nil {
+		return a.Type
+	}
+	attrTypes := map[string]cty.Type{}
+	for name, attr := range nt.Attributes {
+		if attr != nil {
+			attrTypes[name] = attr.ImpliedType()
+		}
+	}
+	ty := cty.Object(attrTypes)
+	switch nt.NestingMode {
+	case schema.NestingList:
+		return cty.List(ty)
+	case schema.NestingSet:
+		return cty.Set(ty)
+	case schema.NestingMap:
+		return cty.Map(ty)
+	default:
+		return ty
+	}
+}
+
+// Block returns the identity schema in form of a block, where the attributes required (or optional) for import are required
+// (or optional), so that it can be compared as the other blocks.
+func (s *IdentitySchema) Block() *BlockSchema {
+	blk := &BlockSchema{Attributes: map[string]*AttributeSchema{}}
+	for name, iattr := range s.Attributes {
+		if iattr == nil {
+			continue
+		}
+		blk.Attributes[name] = &AttributeSchema{
+			Type:     iattr.Type,
+			Required: iattr.RequiredForImport,
+			Optional: iattr.OptionalForImport,
+			Docs:     Docs{Description: iattr.Description},
+		}
+	}
+	return blk
+}
+
+// NewProviderSchema converts the provider schema of the tfpluginschema package, which lacks the information that isn't modelled
+// by that package (e.g. the docs).
+func NewProviderSchema(sch *schema.ProviderSchema) *ProviderSchema {
+	if sch == nil {
+		return nil
+	}
+	out := &ProviderSchema{
+		ResourceSchemas:   newResourceSchemas(sch.ResourceSchemas),
+		DataSourceSchemas: newResourceSchemas(sch.DataSourceSchemas),
+	}
+	if sch.Provider != nil {
+		out.Provider = &ConfigSchema{Block: newBlockSchema(sch.Provider.Block)}
+	}
+	return out
+}
+
+func newResourceSchemas(m map[string]*schema.Resource) map[string]*ResourceSchema {
+	if m == nil {
+		return nil
+	}
+	out := map[string]*ResourceSchema{}
+	for rt, res := range m {
+		if res == nil {
+			out[rt] = nil
+			continue
+		}
+		out[rt] = &ResourceSchema{
+			SchemaVersion: res.SchemaVersion,
+			Block:         newBlockSchema(res.Block),
+		}
+	}
+	return out
+}
+
+func newBlockSchema(blk *schema.Block) *BlockSchema {
+	if blk == nil {
+		return nil
+	}
+	out := &BlockSchema{}
+	if blk.Attributes != nil {
+		out.Attributes = map[string]*AttributeSchema{}
+		for name, attr := range blk.Attributes {
+			if attr == nil {
+				out.Attributes[name] = nil
+				continue
+			}
+			out.Attributes[name] = &AttributeSchema{
+				Type:          attr.Type,
+				Required:      attr.Required,
+				Optional:      attr.Optional,
+				Computed:      attr.Computed,
+				ForceNew:      attr.ForceNew,
+				Default:       attr.Default,
+				Sensitive:     attr.Sensitive,
+				ConflictsWith: attr.ConflictsWith,
+				ExactlyOneOf:  attr.ExactlyOneOf,
+				AtLeastOneOf:  attr.AtLeastOneOf,
+				RequiredWith:  attr.RequiredWith,
+			}
+		}
+	}
+	if blk.NestedBlocks != nil {
+		out.NestedBlocks = map[string]*NestedBlockSchema{}
+		for name, nblk := range blk.NestedBlocks {
+			if nblk == nil {
+				out.NestedBlocks[name] = nil
+				continue
+			}
+			out.NestedBlocks[name] = &NestedBlockSchema{
+				NestingMode:   nblk.NestingMode,
+				Block:         newBlockSchema(nblk.Block),
+				Required:      nblk.Required,
+				Optional:      nblk.Optional,
+				Computed:      nblk.Computed,
+				ForceNew:      nblk.ForceNew,
+				ConflictsWith: nblk.ConflictsWith,
+				ExactlyOneOf:  nblk.ExactlyOneOf,
+				AtLeastOneOf:  nblk.AtLeastOneOf,
+				RequiredWith:  nblk.RequiredWith,
+				MinItems:      nblk.MinItems,
+				MaxItems:      nblk.MaxItems,
+			}
+		}
+	}
+	return out
+}
+
+// unmarshalSchema unmarshals the schema file content.
+func unmarshalSchema(b []byte) (*ProviderSchema, error) {
+	var sch ProviderSchema
+	if err := json.Unmarshal(b, &sch); err != nil {
+		return nil, err
+	}
+	sch.normalizeDocs()
+	return &sch, nil
+}
+
+// normalizeDocs normalizes the docs of every object of the schema, see Docs.normalize.
+func (sch *ProviderSchema) normalizeDocs() {
+	for _, cfg := range []*ConfigSchema{sch.Provider, sch.ProviderMeta} {
+		if cfg != nil {
+			cfg.Block.normalizeDocs()
+		}
+	}
+	for _, m := range []map[string]*ResourceSchema{sch.ResourceSchemas, sch.DataSourceSchemas, sch.EphemeralResourceSchemas} {
+		for _, res := range m {
+			if res != nil {
+				res.Block.normalizeDocs()
+			}
+		}
+	}
+	for _, f := range sch.Functions {
+		if f != nil {
+			f.Docs.normalize()
+		}
+	}
+}
+
+func (blk *BlockSchema) normalizeDocs() {
+	if blk == nil {
+		return
+	}
+	blk.Docs.normalize()
+	normalizeAttributesDocs(blk.Attributes)
+	for _, nblk := range blk.NestedBlocks {
+		if nblk != nil {
+			nblk.Block.normalizeDocs()
+		}
+	}
+}
+
+func normalizeAttributesDocs(attrs map[string]*AttributeSchema) {
+	for _, attr := range attrs {
+		if attr == nil {
+			continue
+		}
+		attr.Docs.normalize()
+		if attr.NestedType != nil {
+			normalizeAttributesDocs(attr.NestedType.Attributes)
+		}
+	}
+}
+
+// blockDocs returns the docs of the block, or the zero value if the block is nil.
+func blockDocs(blk *BlockSchema) Docs {
+	if blk == nil {
+		return Docs{}
+	}
+	return blk.Docs
+}
+
+// providerMetaBlock returns the provider meta schema of the provider schema, or nil if it is absent.
+func (sch *ProviderSchema) providerMetaBlock() *BlockSchema {
+	if sch.ProviderMeta == nil {
+		return nil
+	}
+	return sch.ProviderMeta.Block
+}
+
+// resourceIdentityBlocks returns the resource identity schemas (in form of blocks) of the provider schema.
+func (sch *ProviderSchema) resourceIdentityBlocks() map[string]*BlockSchema {
+	blocks := map[string]*BlockSchema{}
+	for rt, identity := range sch.ResourceIdentitySchemas {
+		if identity != nil {
+			blocks[rt] = identity.Block()
+		}
+	}
+	return blocks
+}
+
+// scopeBlock returns the root block of the scope in the provider schema, or nil if it is absent.
+func (sch *ProviderSchema) scopeBlock(scope Scope) *BlockSchema {
+	switch scope := scope.(type) {
+	case ProviderScope:
+		if sch.Provider != nil {
+			return sch.Provider.Block
+		}
+	case ProviderMetaScope:
+		return sch.providerMetaBlock()
+	case ResourceScope:
+		m := sch.ResourceSchemas
+		switch {
+		case scope.IsDataSource:
+			m = sch.DataSourceSchemas
+		case scope.IsEphemeral:
+			m = sch.EphemeralResourceSchemas
+		}
+		if res := m[scope.Type]; res != nil {
+			return res.Block
+		}
+	case IdentityScope:
+		if identity := sch.ResourceIdentitySchemas[scope.Type]; identity != nil {
+			return identity.Block()
+		}
+	}
+	return nil
+}
+
+// lookupMember returns the attribute or the nested block at the path of the block, where the path can traverse both the nested
+// blocks and the nested attributes. Both are nil if the path can't be resolved.
+func (blk *BlockSchema) lookupMember(path []string) (*AttributeSchema, *NestedBlockSchema) {
+	if blk == nil || len(path) == 0 {
+		return nil, nil
+	}
+	if attr := blk.Attributes[path[0]]; attr != nil {
+		for _, name := range path[1:] {
+			if attr.NestedType == nil {
+				return nil, nil
+			}
+			if attr = attr.NestedType.Attributes[name]; attr == nil {
+				return nil, nil
+			}
+		}
+		return attr, nil
+	}
+	if nblk := blk.NestedBlocks[path[0]]; nblk != nil {
+		if len(path) == 1 {
+			return nil, nblk
+		}
+		return nblk.Block.lookupMember(path[1:])
+	}
+	return nil, nil
+}
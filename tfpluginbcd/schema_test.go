@@ -0,0 +1,555 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/magodo/tfpluginschema/schema"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestSchemaDocs(t *testing.T) {
+	osch, err := unmarshalSchema([]byte(`{
+  "resource_schemas": {
+    "foo_resource": {
+      "block": {
+        "attributes": {
+          "deprecated": {"type": "string", "optional": true, "deprecation_message": "Use new instead"},
+          "not_deprecated": {"type": "string", "optional": true},
+          "to_deprecate": {"type": "string", "optional": true, "description": "old"}
+        }
+      }
+    },
+    "bar_resource": {
+      "block": {
+        "deprecated": true
+      }
+    }
+  }
+}`))
+	require.NoError(t, err)
+	nsch, err := unmarshalSchema([]byte(`{
+  "resource_schemas": {
+    "foo_resource": {
+      "block": {
+        "attributes": {
+          "to_deprecate": {"type": "string", "optional": true, "description": "new", "deprecated": true}
+        }
+      }
+    }
+  }
+}`))
+	require.NoError(t, err)
+
+	changes := Compare(osch, nsch)
+	require.Equal(t, []Change{
+		ResourceChange{
+			Type:     "bar_resource",
+			IsDelete: true,
+			Previous: &Resource{Docs: Docs{Deprecated: true}},
+		},
+		AttributeChange{
+			Scope:    ResourceScope{Type: "foo_resource"},
+			Path:     []string{"deprecated"},
+			IsDelete: true,
+			Previous: &Attribute{Type: cty.String, Optional: true, Docs: Docs{Deprecated: true, DeprecationMessage: "Use new instead"}},
+		},
+		AttributeChange{
+			Scope:    ResourceScope{Type: "foo_resource"},
+			Path:     []string{"not_deprecated"},
+			IsDelete: true,
+			Previous: &Attribute{Type: cty.String, Optional: true},
+		},
+		AttributeChange{
+			Scope:    ResourceScope{Type: "foo_resource"},
+			Path:     []string{"to_deprecate"},
+			IsModify: true,
+			Current:  &Attribute{Type: cty.String, Optional: true, Docs: Docs{Description: "new", Deprecated: true}},
+			Modification: &AttributeModify{
+				DocsModify: DocsModify{
+					Description: &Modification[string]{From: "old", To: "new"},
+					Deprecated:  &Modification[bool]{From: false, To: true},
+				},
+			},
+		},
+	}, changes)
+	require.Equal(t, `Attribute "to_deprecate" of resource foo_resource is changed: description changed, deprecated: false -> true`, changes[3].String())
+
+	results, err := Filter(context.TODO(), changes, []Rule{Rules["R003"], Rules["R015"], Rules["D001"]}, FilterOpt{})
+	require.NoError(t, err)
+	var actual []string
+	for _, res := range results {
+		actual = append(actual, res.Rule+": "+res.Change.String())
+	}
+	require.Equal(t, []string{
+		`R003: Attribute "not_deprecated" of resource foo_resource is deleted`,
+		`D001: Attribute "to_deprecate" of resource foo_resource is changed: description changed, deprecated: false -> true`,
+	}, actual)
+}
+
+func TestSchemaNestedAttributes(t *testing.T) {
+	osch, err := unmarshalSchema([]byte(`{
+  "resource_schemas": {
+    "foo_resource": {
+      "block": {
+        "attributes": {
+          "nested": {
+            "optional": true,
+            "nested_type": {
+              "nesting_mode": 1,
+              "attributes": {
+                "a": {"type": "string", "optional": true},
+                "b": {"type": "number", "optional": true},
+                "sub": {
+                  "optional": true,
+                  "nested_type": {
+                    "nesting_mode": 3,
+                    "attributes": {
+                      "x": {"type": "string", "required": true}
+                    }
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`))
+	require.NoError(t, err)
+	nsch, err := unmarshalSchema([]byte(`{
+  "resource_schemas": {
+    "foo_resource": {
+      "block": {
+        "attributes": {
+          "nested": {
+            "optional": true,
+            "nested_type": {
+              "nesting_mode": 3,
+              "attributes": {
+                "b": {"type": "string", "optional": true},
+                "c": {"type": "string", "optional": true},
+                "sub": {
+                  "optional": true,
+                  "nested_type": {
+                    "nesting_mode": 3,
+                    "attributes": {
+                      "x": {"type": "string", "required": true}
+                    }
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`))
+	require.NoError(t, err)
+
+	nested := nsch.ResourceSchemas["foo_resource"].Block.Attributes["nested"]
+	require.Equal(t, cty.List(cty.Object(map[string]cty.Type{
+		"b":   cty.String,
+		"c":   cty.String,
+		"sub": cty.List(cty.Object(map[string]cty.Type{"x": cty.String})),
+	})), nested.ImpliedType())
+	require.Equal(t, schema.NestingList, NewAttribute(nested).NestingMode)
+
+	var actual []string
+	for _, change := range Compare(osch, nsch) {
+		actual = append(actual, change.String())
+	}
+	require.Equal(t, []string{
+		`Attribute "nested" of resource foo_resource is changed: nesting mode: 1 -> 3`,
+		`Attribute "nested.a" of resource foo_resource is deleted`,
+		`Attribute "nested.b" of resource foo_resource is changed: type: number -> string`,
+		`Attribute "nested.c" of resource foo_resource is added`,
+	}, actual)
+
+	results, err := Filter(context.TODO(), Compare(osch, nsch), []Rule{Rules["R005"], Rules["R016"]}, FilterOpt{})
+	require.NoError(t, err)
+	actual = nil
+	for _, res := range results {
+		actual = append(actual, res.Rule+": "+res.Change.String())
+	}
+	require.Equal(t, []string{
+		`R005: Attribute "nested.b" of resource foo_resource is changed: type: number -> string`,
+		`R016: Attribute "nested" of resource foo_resource is changed: nesting mode: 1 -> 3`,
+	}, actual)
+
+	actual = nil
+	for _, attr := range NormalizeSchema(nsch).Attributes {
+		actual = append(actual, attr.String())
+	}
+	require.Equal(t, []string{
+		`Attribute "nested" of resource foo_resource`,
+		`Attribute "nested.b" of resource foo_resource`,
+		`Attribute "nested.c" of resource foo_resource`,
+		`Attribute "nested.sub" of resource foo_resource`,
+		`Attribute "nested.sub.x" of resource foo_resource`,
+	}, actual)
+
+	// Only the nested attributes have the nesting mode.
+	iresults, err := inspect(context.TODO(), nsch, `x := [a.path | a := input.attributes[_]; a.nesting_mode]`)
+	require.NoError(t, err)
+	require.Len(t, iresults, 1)
+	require.Equal(t, []interface{}{[]interface{}{"nested"}, []interface{}{"nested", "sub"}}, iresults[0].Bindings["x"])
+}
+
+func TestSchemaNestedAttributeConversion(t *testing.T) {
+	osch, err := unmarshalSchema([]byte(`{
+  "resource_schemas": {
+    "foo_resource": {
+      "block": {
+        "block_types": {
+          "rule": {
+            "nesting_mode": 3,
+            "block": {
+              "attributes": {
+                "ip": {"type": "string", "optional": true},
+                "port": {"type": "number", "optional": true}
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`))
+	require.NoError(t, err)
+	nsch, err := unmarshalSchema([]byte(`{
+  "resource_schemas": {
+    "foo_resource": {
+      "block": {
+        "attributes": {
+          "rule": {
+            "optional": true,
+            "nested_type": {
+              "nesting_mode": 3,
+              "attributes": {
+                "ip": {"type": "string", "required": true},
+                "port": {"type": "number", "optional": true}
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`))
+	require.NoError(t, err)
+
+	changes := Compare(osch, nsch)
+	var actual []string
+	for _, change := range changes {
+		actual = append(actual, change.String())
+	}
+	require.Equal(t, []string{
+		`Block "rule" of resource foo_resource is converted to an attribute`,
+		`Attribute "rule.ip" of resource foo_resource is changed: required: false -> true, optional: true -> false`,
+	}, actual)
+
+	results, err := Filter(context.TODO(), changes, []Rule{Rules["R006"], Rules["R017"], Rules["R018"]}, FilterOpt{})
+	require.NoError(t, err)
+	actual = nil
+	for _, res := range results {
+		actual = append(actual, res.Rule+": "+res.Change.String())
+	}
+	require.Equal(t, []string{
+		`R006: Attribute "rule.ip" of resource foo_resource is changed: required: false -> true, optional: true -> false`,
+		`R017: Block "rule" of resource foo_resource is converted to an attribute`,
+	}, actual)
+
+	// The reverse conversion of an optional nested attribute
+	results, err = Filter(context.TODO(), Compare(nsch, osch), []Rule{Rules["R017"], Rules["R018"]}, FilterOpt{})
+	require.NoError(t, err)
+	actual = nil
+	for _, res := range results {
+		actual = append(actual, res.Rule+": "+res.Change.String())
+	}
+	require.Equal(t, []string{
+		`R018: Attribute "rule" of resource foo_resource is converted to a block`,
+	}, actual)
+}
+
+func TestSchemaFunctionsAndEphemeralResources(t *testing.T) {
+	osch, err := unmarshalSchema([]byte(`{
+  "ephemeral_resource_schemas": {
+    "foo_token": {
+      "block": {
+        "attributes": {
+          "value": {"type": "string", "computed": true, "sensitive": true}
+        }
+      }
+    },
+    "foo_secret": {
+      "block": {}
+    }
+  },
+  "functions": {
+    "parse_id": {
+      "parameters": [
+        {"name": "id", "type": "string"}
+      ],
+      "return_type": ["object", {"name": "string"}]
+    },
+    "old_func": {
+      "return_type": "string"
+    }
+  }
+}`))
+	require.NoError(t, err)
+	nsch, err := unmarshalSchema([]byte(`{
+  "ephemeral_resource_schemas": {
+    "foo_token": {
+      "block": {
+        "attributes": {
+          "value": {"type": "number", "computed": true, "sensitive": true}
+        }
+      }
+    }
+  },
+  "functions": {
+    "parse_id": {
+      "parameters": [
+        {"name": "id", "type": "string"},
+        {"name": "strict", "type": "bool"}
+      ],
+      "return_type": ["object", {"name": "string"}],
+      "deprecation_message": "Use parse_resource_id instead"
+    }
+  }
+}`))
+	require.NoError(t, err)
+
+	changes := Compare(osch, nsch)
+	var actual []string
+	for _, change := range changes {
+		actual = append(actual, change.String())
+	}
+	require.Equal(t, []string{
+		`Ephemeral Resource foo_secret is deleted`,
+		`Attribute "value" of ephemeral resource foo_token is changed: type: string -> number`,
+		`Function "old_func" is deleted`,
+		`Function "parse_id" is changed: deprecated: false -> true, deprecation message: "" -> "Use parse_resource_id instead"`,
+		`Parameter 1 ("strict") of function "parse_id" is added`,
+	}, actual)
+
+	// The absent provider config schema is left absent.
+	require.Nil(t, osch.Provider)
+
+	results, err := Filter(context.TODO(), changes, []Rule{Rules["R001"], Rules["R005"], Rules["R011"], Rules["R019"], Rules["R020"], Rules["R022"], Rules["D001"]}, FilterOpt{})
+	require.NoError(t, err)
+	actual = nil
+	for _, res := range results {
+		actual = append(actual, res.Rule+": "+res.Change.String())
+	}
+	require.Equal(t, []string{
+		`R005: Attribute "value" of ephemeral resource foo_token is changed: type: string -> number`,
+		`R019: Ephemeral Resource foo_secret is deleted`,
+		`R020: Function "old_func" is deleted`,
+		`R022: Parameter 1 ("strict") of function "parse_id" is added`,
+		`D001: Function "parse_id" is changed: deprecated: false -> true, deprecation message: "" -> "Use parse_resource_id instead"`,
+	}, actual)
+}
+
+func TestSchemaProviderMetaAndIdentities(t *testing.T) {
+	osch, err := unmarshalSchema([]byte(`{
+  "provider_meta": {
+    "block": {
+      "attributes": {
+        "module_name": {"type": "string", "optional": true}
+      }
+    }
+  },
+  "resource_schemas": {
+    "foo_resource": {"block": {}},
+    "baz_resource": {"block": {}}
+  },
+  "resource_identity_schemas": {
+    "foo_resource": {
+      "version": 0,
+      "attributes": {
+        "name": {"type": "string", "required_for_import": true},
+        "region": {"type": "string", "optional_for_import": true},
+        "zone": {"type": "string", "optional_for_import": true}
+      }
+    },
+    "baz_resource": {
+      "version": 0,
+      "attributes": {
+        "id": {"type": "string", "required_for_import": true}
+      }
+    }
+  }
+}`))
+	require.NoError(t, err)
+	nsch, err := unmarshalSchema([]byte(`{
+  "resource_schemas": {
+    "foo_resource": {"block": {}},
+    "bar_resource": {"block": {}}
+  },
+  "resource_identity_schemas": {
+    "foo_resource": {
+      "version": 0,
+      "attributes": {
+        "name": {"type": "string", "required_for_import": true},
+        "project": {"type": "string", "required_for_import": true},
+        "zone": {"type": "string", "required_for_import": true}
+      }
+    },
+    "bar_resource": {
+      "version": 0,
+      "attributes": {
+        "id": {"type": "string", "required_for_import": true}
+      }
+    }
+  }
+}`))
+	require.NoError(t, err)
+
+	// Neither the identity schema of the added bar_resource nor the one of the deleted baz_resource is compared.
+	changes := Compare(osch, nsch)
+	require.Equal(t, []Change{
+		AttributeChange{
+			Scope:    ProviderMetaScope{},
+			Path:     []string{"module_name"},
+			IsDelete: true,
+			Previous: &Attribute{Type: cty.String, Optional: true},
+		},
+		ResourceChange{
+			Type:     "baz_resource",
+			IsDelete: true,
+			Previous: &Resource{},
+		},
+		ResourceChange{
+			Type:    "bar_resource",
+			IsAdd:   true,
+			Current: &Resource{},
+		},
+		AttributeChange{
+			Scope:    IdentityScope{Type: "foo_resource"},
+			Path:     []string{"region"},
+			IsDelete: true,
+			Previous: &Attribute{Type: cty.String, Optional: true},
+		},
+		AttributeChange{
+			Scope:    IdentityScope{Type: "foo_resource"},
+			Path:     []string{"zone"},
+			IsModify: true,
+			Current:  &Attribute{Type: cty.String, Required: true},
+			Modification: &AttributeModify{
+				Required: &Modification[bool]{From: false, To: true},
+				Optional: &Modification[bool]{From: true, To: false},
+			},
+		},
+		AttributeChange{
+			Scope:   IdentityScope{Type: "foo_resource"},
+			Path:    []string{"project"},
+			IsAdd:   true,
+			Current: &Attribute{Type: cty.String, Required: true},
+		},
+	}, changes)
+
+	results, err := Filter(context.TODO(), changes, []Rule{Rules["R003"], Rules["R008"], Rules["R026"]}, FilterOpt{})
+	require.NoError(t, err)
+	var actual []string
+	for _, res := range results {
+		actual = append(actual, res.Rule+": "+res.Change.String())
+	}
+	require.Equal(t, []string{
+		`R003: Attribute "module_name" of provider meta is deleted`,
+		`R003: Attribute "region" of identity of resource foo_resource is deleted`,
+		`R008: Attribute "project" of identity of resource foo_resource is added`,
+		`R026: Attribute "zone" of identity of resource foo_resource is changed: required: false -> true, optional: true -> false`,
+	}, actual)
+}
+
+func TestNewProviderSchema(t *testing.T) {
+	sch := NewProviderSchema(&schema.ProviderSchema{
+		Provider: &schema.Schema{
+			Block: &schema.Block{
+				Attributes: map[string]*schema.Attribute{
+					"endpoint": {Type: cty.String, Optional: true},
+				},
+			},
+		},
+		ResourceSchemas: map[string]*schema.Resource{
+			"foo_resource": {
+				SchemaVersion: 1,
+				Block: &schema.Block{
+					NestedBlocks: map[string]*schema.NestedBlock{
+						"blk": {
+							NestingMode: schema.NestingList,
+							MaxItems:    1,
+							Block:       &schema.Block{},
+						},
+					},
+				},
+			},
+		},
+	})
+	require.Equal(t, &ProviderSchema{
+		Provider: &ConfigSchema{
+			Block: &BlockSchema{
+				Attributes: map[string]*AttributeSchema{
+					"endpoint": {Type: cty.String, Optional: true},
+				},
+			},
+		},
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				SchemaVersion: 1,
+				Block: &BlockSchema{
+					NestedBlocks: map[string]*NestedBlockSchema{
+						"blk": {
+							NestingMode: schema.NestingList,
+							MaxItems:    1,
+							Block:       &BlockSchema{},
+						},
+					},
+				},
+			},
+		},
+	}, sch)
+}
+
+func TestCompareBuiltSchema(t *testing.T) {
+	// The schemas built in code carry the same information as the ones loaded from the schema files.
+	nested := func(mode schema.NestingMode, desc string) *AttributeSchema {
+		return &AttributeSchema{
+			Optional: true,
+			NestedType: &NestedTypeSchema{
+				NestingMode: mode,
+				Attributes: map[string]*AttributeSchema{
+					"a": {Type: cty.String, Optional: true, Docs: Docs{Description: desc}},
+				},
+			},
+		}
+	}
+	osch := &ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {Block: &BlockSchema{Attributes: map[string]*AttributeSchema{"nested": nested(schema.NestingSingle, "old")}}},
+		},
+		Functions: map[string]*Function{
+			"parse_id": {ReturnType: cty.String},
+		},
+	}
+	nsch := &ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {Block: &BlockSchema{Attributes: map[string]*AttributeSchema{"nested": nested(schema.NestingList, "new")}}},
+		},
+	}
+	var actual []string
+	for _, change := range Compare(osch, nsch) {
+		actual = append(actual, change.String())
+	}
+	require.Equal(t, []string{
+		`Attribute "nested" of resource foo_resource is changed: nesting mode: 1 -> 3`,
+		`Attribute "nested.a" of resource foo_resource is changed: description changed`,
+		`Function "parse_id" is deleted`,
+	}, actual)
+}
@@ -0,0 +1,169 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type Bump int
+
+const (
+	BumpNone Bump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+func (b Bump) String() string {
+	switch b {
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	}
+	return "none"
+}
+
+type SemverOpt struct {
+	// Opt selects the rules, whose matches decide the bump by the rule category, see semverBump. Defaults to the "ga" profile.
+	Opt
+
+	// CurrentVersion and ProposedVersion are the versions of the old and new schemas.
+	// If both are specified, the proposed version is checked to be no smaller than the required bump.
+	CurrentVersion  string
+	ProposedVersion string
+}
+
+// Semver recommends the semantic version bump between the two schemas.
+func Semver(ctx context.Context, opath, npath string, opt SemverOpt) (string, error) {
+	osch, nsch, err := loadSchemas(opath, npath)
+	if err != nil {
+		return "", err
+	}
+	bump, err := semverBump(ctx, *osch, *nsch, opt.Opt)
+	if err != nil {
+		return "", err
+	}
+	if opt.CurrentVersion != "" && opt.ProposedVersion != "" {
+		if err := checkVersionBump(opt.CurrentVersion, opt.ProposedVersion, bump); err != nil {
+			return "", err
+		}
+	}
+	return bump.String(), nil
+}
+
+// semverBump returns major if any breaking change rule (i.e. of the breaking category, or a custom rule) matches, minor if any other rule
+// (e.g. a deprecation or security rule) matches or there are additions, and patch otherwise.
+func semverBump(ctx context.Context, osch, nsch ProviderSchema, opt Opt) (Bump, error) {
+	if !opt.hasRules() {
+		opt.Profile = "ga"
+	}
+	rules, err := buildRules(opt)
+	if err != nil {
+		return BumpNone, err
+	}
+	changes := Compare(&osch, &nsch)
+	results, err := Filter(ctx, changes, rules, opt.filterOpt())
+	if err != nil {
+		return BumpNone, fmt.Errorf("filtering: %v", err)
+	}
+	var hasNonBreaking bool
+	for _, res := range results {
+		if rule, ok := Rules[res.Rule]; ok && rule.Category != RuleCategoryBreaking {
+			hasNonBreaking = true
+			continue
+		}
+		return BumpMajor, nil
+	}
+	if hasNonBreaking {
+		return BumpMinor, nil
+	}
+	for _, change := range changes {
+		switch change := change.(type) {
+		case ProviderChange:
+			if change.IsAdd {
+				return BumpMinor, nil
+			}
+		case ResourceChange:
+			if change.IsAdd {
+				return BumpMinor, nil
+			}
+		case AttributeChange:
+			if change.IsAdd {
+				return BumpMinor, nil
+			}
+		case BlockChange:
+			if change.IsAdd {
+				return BumpMinor, nil
+			}
+		case FunctionChange:
+			if change.IsAdd {
+				return BumpMinor, nil
+			}
+		}
+	}
+	return BumpPatch, nil
+}
+
+// checkVersionBump checks the bump from the current version to the proposed version is no smaller than the required bump.
+func checkVersionBump(current, proposed string, required Bump) error {
+	cv, err := parseVersion(current)
+	if err != nil {
+		return fmt.Errorf("parsing the current version: %v", err)
+	}
+	pv, err := parseVersion(proposed)
+	if err != nil {
+		return fmt.Errorf("parsing the proposed version: %v", err)
+	}
+
+	var actual Bump
+	switch {
+	case pv[0] != cv[0]:
+		if pv[0] < cv[0] {
+			return fmt.Errorf("the proposed version %s is smaller than the current version %s", proposed, current)
+		}
+		actual = BumpMajor
+	case pv[1] != cv[1]:
+		if pv[1] < cv[1] {
+			return fmt.Errorf("the proposed version %s is smaller than the current version %s", proposed, current)
+		}
+		actual = BumpMinor
+	case pv[2] != cv[2]:
+		if pv[2] < cv[2] {
+			return fmt.Errorf("the proposed version %s is smaller than the current version %s", proposed, current)
+		}
+		actual = BumpPatch
+	}
+	if actual == BumpNone {
+		return fmt.Errorf("the proposed version %s is the same as the current version %s", proposed, current)
+	}
+	if actual < required {
+		return fmt.Errorf("the proposed version %s is a %s bump from %s, while a %s bump is required", proposed, actual, current, required)
+	}
+	return nil
+}
+
+// parseVersion parses a semantic version in form of "[v]MAJOR.MINOR.PATCH[-PRERELEASE][+BUILD]".
+func parseVersion(v string) ([3]int, error) {
+	var out [3]int
+	s := strings.TrimPrefix(v, "v")
+	if idx := strings.IndexAny(s, "-+"); idx != -1 {
+		s = s[:idx]
+	}
+	segs := strings.Split(s, ".")
+	if len(segs) != 3 {
+		return out, fmt.Errorf("invalid semantic version %q", v)
+	}
+	for i, seg := range segs {
+		n, err := strconv.Atoi(seg)
+		if err != nil || n < 0 {
+			return out, fmt.Errorf("invalid semantic version %q", v)
+		}
+		out[i] = n
+	}
+	return out, nil
+}
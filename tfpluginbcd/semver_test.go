@@ -0,0 +1,173 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestSemverBump(t *testing.T) {
+	resourceSchema := func(attrs map[string]*AttributeSchema) ProviderSchema {
+		return ProviderSchema{
+			ResourceSchemas: map[string]*ResourceSchema{
+				"foo_resource": {
+					Block: &BlockSchema{
+						Attributes:   attrs,
+						NestedBlocks: map[string]*NestedBlockSchema{},
+					},
+				},
+			},
+		}
+	}
+
+	cases := []struct {
+		name       string
+		opt        Opt
+		osch, nsch ProviderSchema
+		expect     Bump
+	}{
+		{
+			name: "breaking",
+			osch: resourceSchema(map[string]*AttributeSchema{
+				"attr": {Type: cty.String, Optional: true},
+			}),
+			nsch:   resourceSchema(map[string]*AttributeSchema{}),
+			expect: BumpMajor,
+		},
+		{
+			name: "addition",
+			osch: resourceSchema(map[string]*AttributeSchema{}),
+			nsch: resourceSchema(map[string]*AttributeSchema{
+				"attr": {Type: cty.String, Optional: true},
+			}),
+			expect: BumpMinor,
+		},
+		{
+			name: "non-breaking modification",
+			osch: resourceSchema(map[string]*AttributeSchema{
+				"attr": {Type: cty.String, Optional: true},
+			}),
+			nsch: resourceSchema(map[string]*AttributeSchema{
+				"attr": {Type: cty.String, Optional: true, Computed: true},
+			}),
+			expect: BumpPatch,
+		},
+		{
+			name: "deprecation only in strict profile",
+			opt:  Opt{Profile: "strict"},
+			osch: resourceSchema(map[string]*AttributeSchema{
+				"attr": {Type: cty.String, Optional: true},
+			}),
+			nsch: resourceSchema(map[string]*AttributeSchema{
+				"attr": {Type: cty.String, Optional: true, Docs: Docs{Deprecated: true}},
+			}),
+			expect: BumpMinor,
+		},
+		{
+			name: "security only in strict profile",
+			opt:  Opt{Profile: "strict"},
+			osch: resourceSchema(map[string]*AttributeSchema{
+				"password": {Type: cty.String, Optional: true, Sensitive: true},
+			}),
+			nsch: resourceSchema(map[string]*AttributeSchema{
+				"password": {Type: cty.String, Optional: true},
+			}),
+			expect: BumpMinor,
+		},
+		{
+			name: "custom rule",
+			opt:  Opt{CustomRuleExprs: []string{`c.kind == "attribute"; c.is_modify`}},
+			osch: resourceSchema(map[string]*AttributeSchema{
+				"attr": {Type: cty.String, Optional: true},
+			}),
+			nsch: resourceSchema(map[string]*AttributeSchema{
+				"attr": {Type: cty.String, Optional: true, Computed: true},
+			}),
+			expect: BumpMajor,
+		},
+		{
+			name:   "no change",
+			osch:   resourceSchema(map[string]*AttributeSchema{}),
+			nsch:   resourceSchema(map[string]*AttributeSchema{}),
+			expect: BumpPatch,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := semverBump(context.TODO(), tt.osch, tt.nsch, tt.opt)
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, actual)
+		})
+	}
+}
+
+func TestCheckVersionBump(t *testing.T) {
+	cases := []struct {
+		name              string
+		current, proposed string
+		required          Bump
+		hasError          bool
+	}{
+		{
+			name:     "major bump for major",
+			current:  "v1.2.3",
+			proposed: "v2.0.0",
+			required: BumpMajor,
+		},
+		{
+			name:     "minor bump for major",
+			current:  "v1.2.3",
+			proposed: "v1.3.0",
+			required: BumpMajor,
+			hasError: true,
+		},
+		{
+			name:     "major bump for patch",
+			current:  "1.2.3",
+			proposed: "2.0.0-beta1",
+			required: BumpPatch,
+		},
+		{
+			name:     "patch bump for minor",
+			current:  "v1.2.3",
+			proposed: "v1.2.4",
+			required: BumpMinor,
+			hasError: true,
+		},
+		{
+			name:     "same version",
+			current:  "v1.2.3",
+			proposed: "v1.2.3",
+			required: BumpPatch,
+			hasError: true,
+		},
+		{
+			name:     "downgrade",
+			current:  "v1.2.3",
+			proposed: "v1.1.9",
+			required: BumpPatch,
+			hasError: true,
+		},
+		{
+			name:     "invalid version",
+			current:  "v1.2",
+			proposed: "v1.3.0",
+			required: BumpPatch,
+			hasError: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkVersionBump(tt.current, tt.proposed, tt.required)
+			if tt.hasError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/magodo/tfpluginschema/schema"
+)
+
+// tfState is the subset of the Terraform state (version 4) that is used for the impact analysis.
+type tfState struct {
+	Version   int               `json:"version"`
+	Resources []tfStateResource `json:"resources"`
+}
+
+type tfStateResource struct {
+	Module    string            `json:"module,omitempty"`
+	Mode      string            `json:"mode"`
+	Type      string            `json:"type"`
+	Name      string            `json:"name"`
+	Instances []tfStateInstance `json:"instances"`
+}
+
+type tfStateInstance struct {
+	IndexKey      interface{}            `json:"index_key,omitempty"`
+	SchemaVersion int                    `json:"schema_version"`
+	Attributes    map[string]interface{} `json:"attributes"`
+}
+
+// address returns the address of the resource instance, e.g. "module.foo.data.foo_data_source.x[0]".
+func (r tfStateResource) address(inst tfStateInstance) string {
+	addr := r.Type + "." + r.Name
+	if r.Mode == "data" {
+		addr = "data." + addr
+	}
+	if r.Module != "" {
+		addr = r.Module + "." + addr
+	}
+	switch key := inst.IndexKey.(type) {
+	case string:
+		addr += fmt.Sprintf("[%q]", key)
+	case float64:
+		addr += fmt.Sprintf("[%d]", int(key))
+	}
+	return addr
+}
+
+// StateUsage is a resource instance in the Terraform state that is affected by a breaking change,
+// or whose stored schema version is above the schema version of the new schema.
+type StateUsage struct {
+	File    string
+	Address string
+
+	// Rule and Change are the matched breaking change. They are empty for a schema version mismatch.
+	Rule   string
+	Change Change
+
+	// SchemaVersion and NewSchemaVersion are only set for a schema version mismatch.
+	SchemaVersion    int
+	NewSchemaVersion int
+}
+
+func (u StateUsage) String() string {
+	if u.Change == nil {
+		return fmt.Sprintf("%s: %s: the stored schema version %d is above the new schema version %d", u.File, u.Address, u.SchemaVersion, u.NewSchemaVersion)
+	}
+	return fmt.Sprintf("%s: %s: [%s] %s", u.File, u.Address, u.Rule, u.Change.String())
+}
+
+// StateImpact cross-references the breaking changes against the resource instances in the Terraform state files (version 4),
+// and shows the affected instances, including the instances whose stored schema version is above the new schema's.
+func StateImpact(ctx context.Context, opath, npath string, statePaths []string, opt ImpactOpt) (string, error) {
+	osch, nsch, err := loadSchemas(opath, npath)
+	if err != nil {
+		return "", err
+	}
+	var out []string
+	for _, path := range statePaths {
+		state, err := loadState(path)
+		if err != nil {
+			return "", err
+		}
+		usages, err := stateImpact(ctx, *osch, *nsch, path, state, opt)
+		if err != nil {
+			return "", err
+		}
+		for _, u := range usages {
+			out = append(out, u.String())
+		}
+	}
+	return strings.Join(out, "\n"), nil
+}
+
+func loadState(path string) (*tfState, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading the state file %s: %v", path, err)
+	}
+	var state tfState
+	if err := json.Unmarshal(b, &state); err != nil {
+		return nil, fmt.Errorf("unmarshalling the state file %s: %v", path, err)
+	}
+	if state.Version != 4 {
+		return nil, fmt.Errorf("unsupported version of the state file %s: %d", path, state.Version)
+	}
+	return &state, nil
+}
+
+func stateImpact(ctx context.Context, osch, nsch ProviderSchema, file string, state *tfState, opt ImpactOpt) ([]StateUsage, error) {
+	if !opt.hasRules() {
+		opt.Profile = "ga"
+	}
+	rules, err := buildRules(opt.Opt)
+	if err != nil {
+		return nil, err
+	}
+	results, err := Filter(ctx, Compare(&osch, &nsch), rules, opt.filterOpt())
+	if err != nil {
+		return nil, fmt.Errorf("filtering: %v", err)
+	}
+
+	var usages []StateUsage
+	for _, res := range state.Resources {
+		if res.Mode != "managed" && res.Mode != "data" {
+			continue
+		}
+		scope := ResourceScope{Type: res.Type, IsDataSource: res.Mode == "data"}
+		om, nm := osch.ResourceSchemas, nsch.ResourceSchemas
+		if scope.IsDataSource {
+			om, nm = osch.DataSourceSchemas, nsch.DataSourceSchemas
+		}
+		// The values in the state are in the shape of the old schema.
+		var blk *BlockSchema
+		if ores, ok := om[res.Type]; ok {
+			blk = ores.Block
+		}
+		for _, inst := range res.Instances {
+			addr := res.address(inst)
+			if nres, ok := nm[res.Type]; ok && inst.SchemaVersion > nres.SchemaVersion {
+				usages = append(usages, StateUsage{
+					File:             file,
+					Address:          addr,
+					SchemaVersion:    inst.SchemaVersion,
+					NewSchemaVersion: nres.SchemaVersion,
+				})
+			}
+			for _, fres := range results {
+				if !stateInstanceAffected(scope, blk, inst, fres.Change) {
+					continue
+				}
+				usages = append(usages, StateUsage{
+					File:    file,
+					Address: addr,
+					Rule:    fres.Rule,
+					Change:  fres.Change,
+				})
+			}
+		}
+	}
+	return usages, nil
+}
+
+// stateInstanceAffected tells whether the resource instance is affected by the change. For the resource changes, all the instances
+// of the resource are affected, except for the ephemeral resources, which never appear in the state. For the attribute and block changes,
+// only the instances that have the affected values set are affected, where "blk" is the schema of the instance.
+func stateInstanceAffected(scope ResourceScope, blk *BlockSchema, inst tfStateInstance, change Change) bool {
+	switch change := change.(type) {
+	case ResourceChange:
+		return !change.IsEphemeral && change.Type == scope.Type && change.IsDataSource == scope.IsDataSource
+	case AttributeChange:
+		return change.Scope == scope && stateValueSet(inst.Attributes, blk, change.Path)
+	case BlockChange:
+		return change.Scope == scope && stateValueSet(inst.Attributes, blk, change.Path)
+	}
+	return false
+}
+
+// stateValueSet tells whether the value of the path is set in the state value. The null and the zero values (i.e. false, 0,
+// empty string and empty collections) are regarded as not set, as the SDKv2 stores the zero values for the absent arguments.
+// The nesting modes of the nested blocks and nested attributes are looked up in the block schema "blk", so that the elements of the
+// map nested ones are iterated over as the list/set nested ones, instead of having the keys matched against the path.
+func stateValueSet(v interface{}, blk *BlockSchema, path []string) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return len(path) == 0 && v
+	case float64:
+		return len(path) == 0 && v != 0
+	case string:
+		return len(path) == 0 && v != ""
+	case []interface{}:
+		// The nested blocks of list or set nesting mode
+		for _, elem := range v {
+			if stateValueSet(elem, blk, path) {
+				return true
+			}
+		}
+		return false
+	case map[string]interface{}:
+		if len(path) == 0 {
+			return len(v) != 0
+		}
+		var (
+			nblk  *BlockSchema
+			isMap bool
+		)
+		attr, nested := blk.lookupMember(path[:1])
+		switch {
+		case nested != nil:
+			nblk, isMap = nested.Block, nested.NestingMode == schema.NestingMap
+		case attr != nil && attr.NestedType != nil:
+			nblk, isMap = &BlockSchema{Attributes: attr.NestedType.Attributes}, attr.NestedType.NestingMode == schema.NestingMap
+		}
+		if elems, ok := v[path[0]].(map[string]interface{}); ok && isMap {
+			for _, elem := range elems {
+				if stateValueSet(elem, nblk, path[1:]) {
+					return true
+				}
+			}
+			return false
+		}
+		return stateValueSet(v[path[0]], nblk, path[1:])
+	default:
+		return len(path) == 0
+	}
+}
@@ -0,0 +1,205 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/magodo/tfpluginschema/schema"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestStateImpact(t *testing.T) {
+	osch := ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				SchemaVersion: 2,
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"name": {Type: cty.String, Required: true},
+						"old":  {Type: cty.String, Computed: true},
+					},
+					NestedBlocks: map[string]*NestedBlockSchema{
+						"rule": {
+							NestingMode: schema.NestingList,
+							Block: &BlockSchema{
+								Attributes: map[string]*AttributeSchema{
+									"ip": {Type: cty.String, Optional: true},
+								},
+							},
+						},
+					},
+				},
+			},
+			"bar_resource": {
+				Block: &BlockSchema{},
+			},
+		},
+	}
+	nsch := ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				SchemaVersion: 1,
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"name": {Type: cty.String, Required: true},
+					},
+					NestedBlocks: map[string]*NestedBlockSchema{
+						"rule": {
+							NestingMode: schema.NestingList,
+							Block:       &BlockSchema{},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	stateJSON := `{
+  "version": 4,
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "foo_resource",
+      "name": "a",
+      "instances": [
+        {
+          "index_key": 0,
+          "schema_version": 2,
+          "attributes": {"name": "a", "old": "x", "rule": [{"ip": "1.2.3.4"}]}
+        },
+        {
+          "index_key": 1,
+          "schema_version": 1,
+          "attributes": {"name": "b", "old": "", "rule": []}
+        }
+      ]
+    },
+    {
+      "module": "module.m",
+      "mode": "managed",
+      "type": "bar_resource",
+      "name": "b",
+      "instances": [
+        {
+          "index_key": "k",
+          "schema_version": 0,
+          "attributes": {}
+        }
+      ]
+    }
+  ]
+}`
+	var state tfState
+	require.NoError(t, json.Unmarshal([]byte(stateJSON), &state))
+
+	usages, err := stateImpact(context.TODO(), osch, nsch, "terraform.tfstate", &state, ImpactOpt{Opt: Opt{Rules: []string{"R001", "R003", "R010"}}})
+	require.NoError(t, err)
+
+	var actual []string
+	for _, u := range usages {
+		actual = append(actual, u.String())
+	}
+	require.Equal(t, []string{
+		`terraform.tfstate: foo_resource.a[0]: the stored schema version 2 is above the new schema version 1`,
+		`terraform.tfstate: foo_resource.a[0]: [R003] Attribute "old" of resource foo_resource is deleted`,
+		`terraform.tfstate: foo_resource.a[0]: [R003] Attribute "rule.ip" of resource foo_resource is deleted`,
+		`terraform.tfstate: foo_resource.a[0]: [R010] Resource foo_resource is changed: schema version: 2 -> 1`,
+		`terraform.tfstate: foo_resource.a[1]: [R010] Resource foo_resource is changed: schema version: 2 -> 1`,
+		`terraform.tfstate: module.m.bar_resource.b["k"]: [R001] Resource bar_resource is deleted`,
+	}, actual)
+}
+
+func TestStateImpactEphemeralResource(t *testing.T) {
+	resourceSchema := func() *ResourceSchema {
+		return &ResourceSchema{
+			Block: &BlockSchema{
+				Attributes: map[string]*AttributeSchema{
+					"value": {Type: cty.String, Computed: true},
+				},
+			},
+		}
+	}
+	osch := ProviderSchema{
+		ResourceSchemas:          map[string]*ResourceSchema{"foo_secret": resourceSchema()},
+		EphemeralResourceSchemas: map[string]*ResourceSchema{"foo_secret": resourceSchema()},
+	}
+	nsch := ProviderSchema{
+		ResourceSchemas: map[string]*ResourceSchema{"foo_secret": resourceSchema()},
+	}
+
+	stateJSON := `{
+  "version": 4,
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "foo_secret",
+      "name": "x",
+      "instances": [
+        {
+          "schema_version": 0,
+          "attributes": {"value": "v"}
+        }
+      ]
+    }
+  ]
+}`
+	var state tfState
+	require.NoError(t, json.Unmarshal([]byte(stateJSON), &state))
+
+	// The deletion of the ephemeral resource doesn't affect the managed resource of the same type
+	usages, err := stateImpact(context.TODO(), osch, nsch, "terraform.tfstate", &state, ImpactOpt{Opt: Opt{Profile: "strict"}})
+	require.NoError(t, err)
+	require.Empty(t, usages)
+}
+
+func TestStateValueSet(t *testing.T) {
+	cases := []struct {
+		name   string
+		value  string
+		path   []string
+		expect bool
+	}{
+		{name: "null", value: `{"a": null}`, path: []string{"a"}, expect: false},
+		{name: "false", value: `{"a": false}`, path: []string{"a"}, expect: false},
+		{name: "true", value: `{"a": true}`, path: []string{"a"}, expect: true},
+		{name: "zero", value: `{"a": 0}`, path: []string{"a"}, expect: false},
+		{name: "non-zero", value: `{"a": 1.5}`, path: []string{"a"}, expect: true},
+		{name: "empty string", value: `{"a": ""}`, path: []string{"a"}, expect: false},
+		{name: "string", value: `{"a": "x"}`, path: []string{"a"}, expect: true},
+		{name: "empty list", value: `{"a": []}`, path: []string{"a"}, expect: false},
+		{name: "empty map", value: `{"a": {}}`, path: []string{"a"}, expect: false},
+		{name: "absent", value: `{}`, path: []string{"a"}, expect: false},
+		{name: "nested zero values", value: `{"a": [{"b": false}, {"b": 0}]}`, path: []string{"a", "b"}, expect: false},
+		{name: "nested set value", value: `{"a": [{"b": false}, {"b": true}]}`, path: []string{"a", "b"}, expect: true},
+		{name: "map block zero values", value: `{"m": {"k1": {"b": ""}, "k2": {"b": ""}}}`, path: []string{"m", "b"}, expect: false},
+		{name: "map block set value", value: `{"m": {"k1": {"b": ""}, "k2": {"b": "x"}}}`, path: []string{"m", "b"}, expect: true},
+		{name: "map block key", value: `{"m": {"b": {"b": ""}}}`, path: []string{"m", "b"}, expect: false},
+		{name: "map nested attribute set value", value: `{"na": {"k": {"x": 1}}}`, path: []string{"na", "x"}, expect: true},
+		{name: "object attribute", value: `{"o": {"x": 1}}`, path: []string{"o", "x"}, expect: true},
+	}
+	blk := &BlockSchema{
+		Attributes: map[string]*AttributeSchema{
+			"na": {NestedType: &NestedTypeSchema{
+				NestingMode: schema.NestingMap,
+				Attributes:  map[string]*AttributeSchema{"x": {Type: cty.Number, Optional: true}},
+			}},
+		},
+		NestedBlocks: map[string]*NestedBlockSchema{
+			"m": {
+				NestingMode: schema.NestingMap,
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{"b": {Type: cty.String, Optional: true}},
+				},
+			},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var v interface{}
+			require.NoError(t, json.Unmarshal([]byte(tt.value), &v))
+			require.Equal(t, tt.expect, stateValueSet(v, blk, tt.path))
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type UpgradeGuideOpt struct {
+	// Opt selects the rules, whose matches are regarded as breaking changes. Defaults to the "ga" profile.
+	Opt
+
+	// Title is the title of the upgrade guide.
+	Title string
+
+	// NotesFile is the path to a JSON file of hand-written migration hints, see UpgradeGuideNotes.
+	NotesFile string
+}
+
+// UpgradeGuideNotes are hand-written migration hints, which are keyed by the changed object
+// (i.e. "provider", "provider-meta", "resource/<type>", "data-source/<type>", "ephemeral-resource/<type>",
+// "resource-identity/<type>" or "function/<name>"), then by the dot separated path of the attribute or block.
+// The empty path is for the object itself.
+type UpgradeGuideNotes map[string]map[string]string
+
+func UpgradeGuide(ctx context.Context, opath, npath string, opt UpgradeGuideOpt) (string, error) {
+	osch, nsch, err := loadSchemas(opath, npath)
+	if err != nil {
+		return "", err
+	}
+	var notes UpgradeGuideNotes
+	if opt.NotesFile != "" {
+		b, err := os.ReadFile(opt.NotesFile)
+		if err != nil {
+			return "", fmt.Errorf("reading the notes file %s: %v", opt.NotesFile, err)
+		}
+		if err := json.Unmarshal(b, &notes); err != nil {
+			return "", fmt.Errorf("unmarshalling the notes file %s: %v", opt.NotesFile, err)
+		}
+	}
+	return upgradeGuide(ctx, *osch, *nsch, opt, notes)
+}
+
+func upgradeGuide(ctx context.Context, osch, nsch ProviderSchema, opt UpgradeGuideOpt, notes UpgradeGuideNotes) (string, error) {
+	if !opt.hasRules() {
+		opt.Profile = "ga"
+	}
+	rules, err := buildRules(opt.Opt)
+	if err != nil {
+		return "", err
+	}
+	results, err := Filter(ctx, Compare(&osch, &nsch), rules, opt.filterOpt())
+	if err != nil {
+		return "", fmt.Errorf("filtering: %v", err)
+	}
+
+	// Group the findings by the changed object
+	groups := map[string][]string{}
+	entries := map[string]ChangelogEntry{}
+	for _, res := range results {
+		entry := newChangelogEntry(res.Change)
+		entries[entry.Object] = entry
+		// The note of the object itself is rendered under the object's heading
+		var note string
+		if entry.Path != "" {
+			note = notes[entry.Object][entry.Path]
+		}
+		groups[entry.Object] = append(groups[entry.Object], upgradeGuideItem(res, entry, note))
+	}
+
+	title := opt.Title
+	if title == "" {
+		title = "Upgrade Guide"
+	}
+	out := "# " + title + "\n"
+	if len(groups) == 0 {
+		out += "\nThere is no breaking change.\n"
+		return out, nil
+	}
+	for _, object := range mapSortedKeys(groups) {
+		entry := entries[object]
+		switch {
+		case object == "provider-meta":
+			out += "\n## Provider Meta\n"
+		case strings.HasPrefix(object, "resource-identity/"):
+			out += fmt.Sprintf("\n## Resource Identity: `%s`\n", entry.Type)
+		case entry.Type == "":
+			out += "\n## Provider\n"
+		case entry.IsDataSource:
+			out += fmt.Sprintf("\n## Data Source: `%s`\n", entry.Type)
+		case entry.IsEphemeral:
+			out += fmt.Sprintf("\n## Ephemeral Resource: `%s`\n", entry.Type)
+		case entry.Kind == ChangeKindFunction || entry.Kind == ChangeKindFunctionParameter:
+			out += fmt.Sprintf("\n## Function: `%s`\n", entry.Type)
+		default:
+			out += fmt.Sprintf("\n## Resource: `%s`\n", entry.Type)
+		}
+		if note := notes[object][""]; note != "" {
+			out += "\n" + note + "\n"
+		}
+		out += "\n" + strings.Join(groups[object], "\n") + "\n"
+	}
+	return out, nil
+}
+
+func upgradeGuideItem(res FilterResult, entry ChangelogEntry, note string) string {
+	var (
+		subject string
+		verb    string
+		items   []string
+	)
+	switch change := res.Change.(type) {
+	case ProviderChange:
+		subject = "The provider config"
+		verb = changeVerb(change.IsAdd, change.IsDelete, false)
+	case ResourceChange:
+		subject = "The resource"
+		if change.IsDataSource {
+			subject = "The data source"
+		}
+		if change.IsEphemeral {
+			subject = "The ephemeral resource"
+		}
+		verb = changeVerb(change.IsAdd, change.IsDelete, change.IsModify)
+		if change.Modification != nil {
+			items = change.Modification.items()
+		}
+	case AttributeChange:
+		subject = fmt.Sprintf("Attribute `%s`", entry.Path)
+		verb = changeVerb(change.IsAdd, change.IsDelete, change.IsModify)
+		if change.Modification != nil {
+			items = change.Modification.items()
+		}
+	case BlockChange:
+		subject = fmt.Sprintf("Block `%s`", entry.Path)
+		verb = changeVerb(change.IsAdd, change.IsDelete, change.IsModify)
+		if change.Modification != nil {
+			items = change.Modification.items()
+		}
+	case ConversionChange:
+		subject = fmt.Sprintf("Attribute `%s`", entry.Path)
+		if change.IsBlockToAttribute {
+			subject = fmt.Sprintf("Block `%s`", entry.Path)
+		}
+		verb = change.verb()
+	case FunctionChange:
+		subject = "The function"
+		verb = changeVerb(change.IsAdd, change.IsDelete, change.IsModify)
+		if change.Modification != nil {
+			items = change.Modification.items()
+		}
+	case FunctionParameterChange:
+		subject = fmt.Sprintf("Parameter `%s`", entry.Path)
+		if change.IsVariadic {
+			subject = fmt.Sprintf("Variadic parameter `%s`", entry.Path)
+		}
+		verb = changeVerb(change.IsAdd, change.IsDelete, change.IsModify)
+		if change.Modification != nil {
+			items = change.Modification.items()
+		}
+	}
+
+	reason := res.Rule
+	if rule, ok := Rules[res.Rule]; ok {
+		reason = fmt.Sprintf("%s (%s)", rule.Description, rule.ID)
+	}
+
+	out := fmt.Sprintf("* %s is %s: %s", subject, verb, reason)
+	for _, item := range items {
+		out += "\n    * " + item
+	}
+	if note != "" {
+		out += "\n\n    " + strings.ReplaceAll(note, "\n", "\n    ")
+	}
+	return out
+}
+
+func changeVerb(isAdd, isDelete, isModify bool) string {
+	switch {
+	case isAdd:
+		return "added"
+	case isDelete:
+		return "deleted"
+	case isModify:
+		return "changed"
+	}
+	return ""
+}
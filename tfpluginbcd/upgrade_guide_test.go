@@ -0,0 +1,124 @@
+package tfpluginbcd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestUpgradeGuide(t *testing.T) {
+	osch := ProviderSchema{
+		Provider: &ConfigSchema{
+			Block: &BlockSchema{
+				Attributes: map[string]*AttributeSchema{
+					"endpoint": {
+						Type:     cty.String,
+						Optional: true,
+					},
+				},
+				NestedBlocks: map[string]*NestedBlockSchema{},
+			},
+		},
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"attr": {
+							Type:     cty.Bool,
+							Optional: true,
+						},
+					},
+					NestedBlocks: map[string]*NestedBlockSchema{},
+				},
+			},
+			"bar_resource": {
+				Block: &BlockSchema{
+					Attributes:   map[string]*AttributeSchema{},
+					NestedBlocks: map[string]*NestedBlockSchema{},
+				},
+			},
+		},
+	}
+	nsch := ProviderSchema{
+		Provider: &ConfigSchema{
+			Block: &BlockSchema{
+				Attributes:   map[string]*AttributeSchema{},
+				NestedBlocks: map[string]*NestedBlockSchema{},
+			},
+		},
+		ResourceSchemas: map[string]*ResourceSchema{
+			"foo_resource": {
+				SchemaVersion: 1,
+				Block: &BlockSchema{
+					Attributes: map[string]*AttributeSchema{
+						"attr": {
+							Type:     cty.String,
+							Required: true,
+						},
+					},
+					NestedBlocks: map[string]*NestedBlockSchema{},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name   string
+		opt    UpgradeGuideOpt
+		notes  UpgradeGuideNotes
+		expect string
+	}{
+		{
+			name: "default",
+			notes: UpgradeGuideNotes{
+				"resource/bar_resource": {
+					"": "Use `foo_resource` instead.",
+				},
+				"resource/foo_resource": {
+					"attr": "Quote the value.",
+				},
+			},
+			expect: "# Upgrade Guide" + `
+
+## Provider
+
+* Attribute ` + "`endpoint`" + ` is deleted: An attribute is deleted without prior deprecation (R003)
+
+## Resource: ` + "`bar_resource`" + `
+
+Use ` + "`foo_resource`" + ` instead.
+
+* The resource is deleted: A resource is deleted (R001)
+
+## Resource: ` + "`foo_resource`" + `
+
+* Attribute ` + "`attr`" + ` is changed: The type of an attribute is changed (R005)
+    * type: bool -> string
+    * required: false -> true
+    * optional: true -> false
+
+    Quote the value.
+`,
+		},
+		{
+			name: "no breaking change",
+			opt: UpgradeGuideOpt{
+				Title: "v2.0 Upgrade Guide",
+				Opt: Opt{
+					Rules: []string{"R002"},
+				},
+			},
+			expect: "# v2.0 Upgrade Guide\n\nThere is no breaking change.\n",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := upgradeGuide(context.TODO(), osch, nsch, tt.opt, tt.notes)
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, actual)
+		})
+	}
+}
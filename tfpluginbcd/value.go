@@ -0,0 +1,139 @@
+package tfpluginbcd
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// ctyValue converts a value decoded from JSON (e.g. the default value of an attribute) to a cty value of the type "ty".
+// In case the value can't be converted to that type, it is converted to a value of its implied type instead.
+func ctyValue(v interface{}, ty cty.Type) cty.Value {
+	if ty == cty.NilType {
+		ty = cty.DynamicPseudoType
+	}
+	if v == nil {
+		return cty.NullVal(ty)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return cty.DynamicVal
+	}
+	if ty != cty.DynamicPseudoType {
+		if val, err := ctyjson.Unmarshal(b, ty); err == nil {
+			return val
+		}
+	}
+	ity, err := ctyjson.ImpliedType(b)
+	if err != nil {
+		return cty.DynamicVal
+	}
+	val, err := ctyjson.Unmarshal(b, ity)
+	if err != nil {
+		return cty.DynamicVal
+	}
+	return val
+}
+
+// ctyValueEqual tells whether two cty values are equal. Null values are regarded as equal regardless of their types.
+func ctyValueEqual(a, b cty.Value) bool {
+	if a.IsNull() && b.IsNull() {
+		return true
+	}
+	return a.RawEquals(b)
+}
+
+// valueEqual tells whether two values decoded from JSON are equal, by comparing them as cty values of the types "aty" and "bty".
+// In case either of them can't be converted to a cty value, the raw values are compared instead.
+func valueEqual(a interface{}, aty cty.Type, b interface{}, bty cty.Type) bool {
+	va, vb := ctyValue(a, aty), ctyValue(b, bty)
+	if !va.IsKnown() || !vb.IsKnown() {
+		return reflect.DeepEqual(a, b)
+	}
+	return ctyValueEqual(va, vb)
+}
+
+// DefaultValue is the default value of an attribute, converted to a cty value of the attribute type (see ctyValue).
+// It is marshalled to JSON as the plain value, and formatted in HCL-like syntax.
+type DefaultValue struct {
+	cty.Value
+}
+
+func newDefaultValue(v interface{}, ty cty.Type) DefaultValue {
+	return DefaultValue{Value: ctyValue(v, ty)}
+}
+
+func (v DefaultValue) String() string {
+	return formatCtyValue(v.Value)
+}
+
+func (v DefaultValue) MarshalJSON() ([]byte, error) {
+	if v.Value == cty.NilVal || v.IsNull() || !v.IsWhollyKnown() {
+		return []byte("null"), nil
+	}
+	return ctyjson.Marshal(v.Value, v.Type())
+}
+
+// formatCtyValue formats a cty value in HCL-like syntax.
+func formatCtyValue(v cty.Value) string {
+	if v.IsNull() {
+		return "null"
+	}
+	if !v.IsKnown() {
+		return "(known after apply)"
+	}
+
+	ty := v.Type()
+	switch {
+	case ty == cty.String:
+		return fmt.Sprintf("%q", v.AsString())
+	case ty == cty.Number:
+		return v.AsBigFloat().Text('f', -1)
+	case ty == cty.Bool:
+		if v.True() {
+			return "true"
+		}
+		return "false"
+	case ty.IsListType() || ty.IsSetType() || ty.IsTupleType():
+		var l []string
+		for it := v.ElementIterator(); it.Next(); {
+			_, ev := it.Element()
+			l = append(l, formatCtyValue(ev))
+		}
+		return "[" + strings.Join(l, ", ") + "]"
+	case ty.IsMapType() || ty.IsObjectType():
+		m := map[string]string{}
+		for it := v.ElementIterator(); it.Next(); {
+			k, ev := it.Element()
+			m[k.AsString()] = formatCtyValue(ev)
+		}
+		if len(m) == 0 {
+			return "{}"
+		}
+		var l []string
+		for _, k := range mapSortedKeys(m) {
+			l = append(l, fmt.Sprintf("%s = %s", formatObjectKey(k), m[k]))
+		}
+		return "{ " + strings.Join(l, ", ") + " }"
+	}
+	return v.GoString()
+}
+
+func formatObjectKey(k string) string {
+	if k == "" {
+		return `""`
+	}
+	for i, r := range k {
+		isLetter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		isDigit := r >= '0' && r <= '9'
+		if isLetter || (i > 0 && (isDigit || r == '-')) {
+			continue
+		}
+		return fmt.Sprintf("%q", k)
+	}
+	return k
+}
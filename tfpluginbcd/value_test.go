@@ -0,0 +1,192 @@
+package tfpluginbcd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestDefaultValueString(t *testing.T) {
+	cases := []struct {
+		name   string
+		value  interface{}
+		ty     cty.Type
+		expect string
+	}{
+		{
+			name:   "null",
+			value:  nil,
+			expect: "null",
+		},
+		{
+			name:   "string",
+			value:  "foo",
+			expect: `"foo"`,
+		},
+		{
+			name:   "number",
+			value:  1.5,
+			expect: "1.5",
+		},
+		{
+			name:   "bool",
+			value:  true,
+			expect: "true",
+		},
+		{
+			name:   "list",
+			value:  []interface{}{1, "a"},
+			expect: `[1, "a"]`,
+		},
+		{
+			name:   "map",
+			value:  map[string]interface{}{"b": 1, "a": "x", "c d": false},
+			expect: `{ a = "x", b = 1, "c d" = false }`,
+		},
+		{
+			name:   "empty map",
+			value:  map[string]interface{}{},
+			expect: "{}",
+		},
+		{
+			name:   "number of string type",
+			value:  1,
+			ty:     cty.String,
+			expect: `"1"`,
+		},
+		{
+			name:   "list of set type",
+			value:  []interface{}{"b", "a", "b"},
+			ty:     cty.Set(cty.String),
+			expect: `["a", "b"]`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expect, newDefaultValue(tt.value, tt.ty).String())
+		})
+	}
+}
+
+func TestDefaultValueJSON(t *testing.T) {
+	cases := []struct {
+		name   string
+		value  interface{}
+		ty     cty.Type
+		expect string
+	}{
+		{
+			name:   "null",
+			value:  nil,
+			ty:     cty.String,
+			expect: `null`,
+		},
+		{
+			name:   "number of string type",
+			value:  1,
+			ty:     cty.String,
+			expect: `"1"`,
+		},
+		{
+			name:   "list of set type",
+			value:  []interface{}{"b", "a", "b"},
+			ty:     cty.Set(cty.String),
+			expect: `["a","b"]`,
+		},
+		{
+			name:   "unconvertible",
+			value:  []interface{}{"a"},
+			ty:     cty.String,
+			expect: `["a"]`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(newDefaultValue(tt.value, tt.ty))
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, string(b))
+		})
+	}
+}
+
+func TestValueEqual(t *testing.T) {
+	cases := []struct {
+		name   string
+		a, b   interface{}
+		aty    cty.Type
+		bty    cty.Type
+		expect bool
+	}{
+		{
+			name:   "both null of different types",
+			aty:    cty.String,
+			bty:    cty.Number,
+			expect: true,
+		},
+		{
+			name:   "int and float",
+			a:      1,
+			b:      float64(1),
+			aty:    cty.Number,
+			bty:    cty.Number,
+			expect: true,
+		},
+		{
+			name:   "set ignores order",
+			a:      []interface{}{"a", "b"},
+			b:      []interface{}{"b", "a"},
+			aty:    cty.Set(cty.String),
+			bty:    cty.Set(cty.String),
+			expect: true,
+		},
+		{
+			name:   "list respects order",
+			a:      []interface{}{"a", "b"},
+			b:      []interface{}{"b", "a"},
+			aty:    cty.List(cty.String),
+			bty:    cty.List(cty.String),
+			expect: false,
+		},
+		{
+			name:   "null and non-null",
+			b:      "a",
+			aty:    cty.String,
+			bty:    cty.String,
+			expect: false,
+		},
+		{
+			name:   "untyped map",
+			a:      map[string]interface{}{"a": 1},
+			b:      map[string]interface{}{"a": 1},
+			expect: true,
+		},
+		{
+			name:   "unconvertible values",
+			a:      map[string]interface{}{"a": make(chan int)},
+			b:      map[string]interface{}{"b": make(chan int)},
+			expect: false,
+		},
+		{
+			name:   "unconvertible and convertible values",
+			a:      map[string]interface{}{"a": make(chan int)},
+			b:      map[string]interface{}{"a": 1},
+			expect: false,
+		},
+		{
+			name:   "same unconvertible values",
+			a:      map[string]interface{}{"a": nil, "b": []chan int{}},
+			b:      map[string]interface{}{"a": nil, "b": []chan int{}},
+			expect: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expect, valueEqual(tt.a, tt.aty, tt.b, tt.bty))
+		})
+	}
+}